@@ -0,0 +1,215 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"github-updates/internal/pkg/charts"
+	"github-updates/internal/pkg/configs"
+	"github-updates/internal/pkg/feeds"
+	"github-updates/internal/pkg/storage"
+	"io/ioutil"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// defaultChartWeeks is the length of the weekly activity sparkline
+// read from the history
+const defaultChartWeeks = 12
+
+// drawCharts writes the SVG charts of every organization of the
+// report next to each of its summaries, so that the file names set
+// for the templates resolve from every output folder. The charts
+// with nothing to show are not drawn.
+func drawCharts(
+	config configs.Configuration,
+	outputs []configs.ReportOutput,
+	v interface{},
+) error {
+	chartsConfig := config.GlobalConfiguration.Charts
+	if !chartsConfig.Enabled || len(outputs) == 0 {
+		return nil
+	}
+	directories, err := chartDirectories(outputs)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	start := now.AddDate(0, 0, -config.GlobalConfiguration.DaysCount)
+
+	switch details := v.(type) {
+	case []configs.PullRequestDetails:
+		var store *storage.Store
+		if config.GlobalConfiguration.Storage.SQLite.Enabled {
+			store, err = storage.Open(config.GlobalConfiguration.Storage.SQLite.Path)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+		}
+		for index := range details {
+			org := details[index].Organization
+			chartFiles := &configs.Charts{}
+			bars := prsPerRepository(details[index])
+			if len(bars) > 0 {
+				chartFiles.PRsPerRepository = org + "-prs-per-repository.svg"
+				err = writeChart(directories, chartFiles.PRsPerRepository,
+					charts.BarChart("PRs per repository", bars))
+				if err != nil {
+					return err
+				}
+			}
+			weekly, err := weeklyActivity(store, chartsConfig, details[index], start, now)
+			if err != nil {
+				return err
+			}
+			if hasActivity(weekly) {
+				chartFiles.WeeklyActivity = org + "-weekly-activity.svg"
+				err = writeChart(directories, chartFiles.WeeklyActivity,
+					charts.Sparkline("PRs opened per week", weekly))
+				if err != nil {
+					return err
+				}
+			}
+			if chartFiles.PRsPerRepository != "" || chartFiles.WeeklyActivity != "" {
+				details[index].Charts = chartFiles
+			}
+		}
+	case []configs.ReleaseDetails:
+		for index := range details {
+			org := details[index].Organization
+			var rows []charts.TimelineRow
+			for _, releaseList := range details[index].ReleaseRepoLists {
+				row := charts.TimelineRow{Label: releaseList.Repository}
+				for _, release := range releaseList.Releases {
+					row.Dates = append(row.Dates, release.GetPublishedAt().Time)
+				}
+				if len(row.Dates) > 0 {
+					rows = append(rows, row)
+				}
+			}
+			if len(rows) == 0 {
+				continue
+			}
+			chartFiles := &configs.Charts{ReleaseTimeline: org + "-release-timeline.svg"}
+			err = writeChart(directories, chartFiles.ReleaseTimeline,
+				charts.Timeline("Releases", rows, start, now))
+			if err != nil {
+				return err
+			}
+			details[index].Charts = chartFiles
+		}
+	}
+	return nil
+}
+
+// chartDirectories are the folders of the outputs which render a
+// template, each created and listed once
+func chartDirectories(outputs []configs.ReportOutput) ([]string, error) {
+	var directories []string
+	seen := map[string]bool{}
+	for _, output := range outputs {
+		if feeds.IsFeed(output.Format) ||
+			output.Format == configs.FormatCSV || output.Format == configs.FormatTSV {
+			continue
+		}
+		directory := filepath.Clean(filepath.Dir(output.Path))
+		if seen[directory] {
+			continue
+		}
+		seen[directory] = true
+		err := os.MkdirAll(directory, 0755)
+		if err != nil {
+			return nil, err
+		}
+		directories = append(directories, directory)
+	}
+	return directories, nil
+}
+
+// hasActivity tells if any week of the series has a PR
+func hasActivity(weekly []int) bool {
+	for _, count := range weekly {
+		if count > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// prsPerRepository counts the PRs of every repository, the most
+// active repository first
+func prsPerRepository(details configs.PullRequestDetails) []charts.Bar {
+	var bars []charts.Bar
+	for _, prList := range details.PrRepoLists {
+		if len(prList.PRs) > 0 {
+			bars = append(bars, charts.Bar{Label: prList.Repository, Value: float64(len(prList.PRs))})
+		}
+	}
+	sort.SliceStable(bars, func(i, j int) bool {
+		return bars[i].Value > bars[j].Value
+	})
+	return bars
+}
+
+// weeklyActivity counts the PRs opened per week. The history gives
+// the configured weeks when it is kept, else the PRs of the report
+// are counted over the reported days.
+func weeklyActivity(
+	store *storage.Store,
+	chartsConfig configs.ChartsConfiguration,
+	details configs.PullRequestDetails,
+	start time.Time,
+	now time.Time,
+) ([]int, error) {
+	if store != nil {
+		weeks := chartsConfig.Weeks
+		if weeks <= 0 {
+			weeks = defaultChartWeeks
+		}
+		return store.PullRequestsPerWeek(details.Organization, weeks, now)
+	}
+	weeks := int(math.Max(1, math.Ceil(now.Sub(start).Hours()/(24*7))))
+	counts := make([]int, weeks)
+	// the last bucket ends now
+	first := now.AddDate(0, 0, -7*weeks)
+	for _, prList := range details.PrRepoLists {
+		for _, pr := range prList.PRs {
+			week := int(pr.GetCreatedAt().Sub(first).Hours() / (24 * 7))
+			if week >= 0 && week < weeks {
+				counts[week]++
+			}
+		}
+	}
+	return counts, nil
+}
+
+// writeChart saves the SVG chart into every directory
+func writeChart(directories []string, fileName string, svg string) error {
+	for _, directory := range directories {
+		path := filepath.Join(directory, fileName)
+		log.Printf("Writing the chart %v", path)
+		err := ioutil.WriteFile(path, []byte(svg), 0644)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
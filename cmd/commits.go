@@ -0,0 +1,82 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	client2 "github-updates/internal/pkg/client"
+	"github-updates/internal/pkg/commits"
+	"github-updates/internal/pkg/configs"
+	"github-updates/internal/pkg/utils"
+	"log"
+)
+
+// commitReport writes the commits pushed to the default branch of
+// the repositories of every organization, the repositories with no
+// commit are left out
+func commitReport(
+	config configs.Configuration,
+	client client2.GHClientInterface,
+	repositoryLists map[string][]configs.RepositoryDetails,
+) error {
+	commitConfig := config.Commits
+	if !commitConfig.CommitReportShouldRun {
+		return nil
+	}
+	days := commitConfig.CommitDays
+	if days <= 0 {
+		days = config.GlobalConfiguration.DaysCount
+	}
+
+	var commitList []configs.CommitDetails
+	for _, organization := range config.GlobalConfiguration.Organizations {
+		org := organization.Organization.Github
+		repoDetails := repositoryLists[org]
+		commitDetails := configs.CommitDetails{Organization: org}
+		for _, repo := range repoDetails {
+			if repo.Archived {
+				continue
+			}
+			log.Printf("Listing the commits of %v/%v on %v", org, repo.Name, repo.DefaultBranch)
+			repositoryCommits, err := client.ListCommits(org, repo, days, commitConfig.FilesChanged)
+			if err != nil {
+				return err
+			}
+			repoCommits := commits.Summarize(repo, repositoryCommits, commitConfig.ExcludeBots)
+			if len(repoCommits.Commits) == 0 {
+				continue
+			}
+			commitDetails.CommitLists = append(commitDetails.CommitLists, repoCommits)
+		}
+		commitList = append(commitList, commitDetails)
+	}
+
+	outputs :=
+		summaryOutputs(
+			utils.GetEnvOrDefault(
+				configs.CommitSummaryFilePath,
+				commitConfig.CommitSummaryFileName,
+			),
+			summaryTemplateFile(configs.CommitReport),
+			commitConfig.CommitOutputs,
+		)
+	return generateReport(
+		commitConfig.CommitDataFile,
+		commitList,
+		configs.CommitReport,
+		outputs,
+	)
+}
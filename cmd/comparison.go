@@ -0,0 +1,73 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"github-updates/internal/pkg/comparison"
+	"github-updates/internal/pkg/configs"
+	"github-updates/internal/pkg/schema"
+	"log"
+	"os"
+)
+
+// compareWithPrevious sets the comparison of every organization of
+// the report with the data file of the previous run, it has to run
+// before the data file is replaced
+func compareWithPrevious(
+	config configs.Configuration,
+	dataFileName string,
+	kind string,
+	v interface{},
+) error {
+	if !config.GlobalConfiguration.Comparison.Enabled {
+		return nil
+	}
+	previous, err := schema.Read(dataFileName)
+	if os.IsNotExist(err) {
+		log.Printf("No previous %v data file to compare with", kind)
+		return nil
+	}
+	if err != nil {
+		// the files written before the versioned layout are arrays
+		log.Printf("Nothing to compare with in the previous %v data file: %v", kind, err)
+		return nil
+	}
+	current, err := schema.New(kind, v)
+	if err != nil {
+		return err
+	}
+	deltas := comparison.Compare(previous, current)
+	delta := func(org string) *configs.Delta {
+		orgDelta := deltas[org]
+		return &orgDelta
+	}
+	switch details := v.(type) {
+	case []configs.PullRequestDetails:
+		for index := range details {
+			details[index].Comparison = delta(details[index].Organization)
+		}
+	case []configs.ReleaseDetails:
+		for index := range details {
+			details[index].Comparison = delta(details[index].Organization)
+		}
+	case []configs.IssueDetails:
+		for index := range details {
+			details[index].Comparison = delta(details[index].Organization)
+		}
+	}
+	return nil
+}
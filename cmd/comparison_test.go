@@ -0,0 +1,45 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github-updates/internal/pkg/configs"
+)
+
+func TestCompareWithUnversionedFile(t *testing.T) {
+	dataFile := filepath.Join(t.TempDir(), "prs.json")
+	// the data files were the report arrays before the versioned layout
+	err := ioutil.WriteFile(dataFile, []byte(`[{"organization":"org","prlists":[]}]`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var config configs.Configuration
+	config.GlobalConfiguration.Comparison.Enabled = true
+	details := []configs.PullRequestDetails{{Organization: "org"}}
+
+	err = compareWithPrevious(config, dataFile, configs.PullRequestReport, details)
+	if err != nil {
+		t.Fatalf("comparing with an array data file failed: %v", err)
+	}
+	if details[0].Comparison != nil {
+		t.Errorf("the organization was compared with %+v", details[0].Comparison)
+	}
+}
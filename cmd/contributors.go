@@ -0,0 +1,73 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	client2 "github-updates/internal/pkg/client"
+	"github-updates/internal/pkg/configs"
+	"github-updates/internal/pkg/contributors"
+	"github-updates/internal/pkg/utils"
+	"log"
+	"time"
+)
+
+// contributorReport writes the statistics of the contributors of
+// every organization
+func contributorReport(
+	config configs.Configuration,
+	client client2.GHClientInterface,
+	repositoryLists map[string][]configs.RepositoryDetails,
+) error {
+	contributorConfig := config.Contributors
+	if !contributorConfig.ContributorReportShouldRun {
+		return nil
+	}
+	days := contributorConfig.ContributorDays
+	if days <= 0 {
+		days = config.GlobalConfiguration.DaysCount
+	}
+	startDate := time.Now().AddDate(0, 0, days*-1)
+
+	var contributorList []configs.ContributorDetails
+	for _, organization := range config.GlobalConfiguration.Organizations {
+		org := organization.Organization.Github
+		repos := repositoryNames(repositoryLists[org])
+		log.Printf("Collecting the activity of the contributors of %v", org)
+		activityLists, err := client.ListActivity(org, repos, days)
+		if err != nil {
+			return err
+		}
+		contributorList = append(contributorList,
+			contributors.Statistics(org, activityLists, startDate, contributorConfig))
+	}
+
+	outputs :=
+		summaryOutputs(
+			utils.GetEnvOrDefault(
+				configs.ContributorSummaryFilePath,
+				contributorConfig.ContributorSummaryFileName,
+			),
+			summaryTemplateFile(configs.ContributorReport),
+			contributorConfig.ContributorOutputs,
+		)
+	return generateReport(
+		contributorConfig.ContributorDataFile,
+		contributorList,
+		configs.ContributorReport,
+		outputs,
+	)
+}
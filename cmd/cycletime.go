@@ -0,0 +1,65 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"github-updates/internal/pkg/configs"
+	"github-updates/internal/pkg/metrics"
+	"github-updates/internal/pkg/utils"
+
+	"github.com/google/go-github/v33/github"
+)
+
+// getCycleTime computes the cycle time of every PR of the
+// repository from its reviews
+func getCycleTime(
+	prList configs.PrList,
+	reviews map[int][]github.PullRequestReview,
+) map[int]configs.CycleTime {
+	cycleTimes := map[int]configs.CycleTime{}
+	for _, pr := range prList.PRs {
+		cycleTimes[pr.GetNumber()] = metrics.CycleTime(pr, reviews[pr.GetNumber()])
+	}
+	return cycleTimes
+}
+
+// cycleTimeReport writes the cycle time statistics of the
+// repositories collected with the PRs
+func cycleTimeReport(
+	config configs.Configuration,
+	expectedPrList []configs.PullRequestDetails,
+) error {
+	cycleTimeConfig := config.PullRequests.PRCycleTime
+	if !cycleTimeConfig.Enabled {
+		return nil
+	}
+	outputs :=
+		summaryOutputs(
+			utils.GetEnvOrDefault(
+				configs.CycleTimeSummaryFilePath,
+				cycleTimeConfig.SummaryFileName,
+			),
+			summaryTemplateFile(configs.CycleTimeReport),
+			cycleTimeConfig.Outputs,
+		)
+	return generateReport(
+		cycleTimeConfig.DataFile,
+		expectedPrList,
+		configs.CycleTimeReport,
+		outputs,
+	)
+}
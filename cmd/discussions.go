@@ -0,0 +1,147 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	client2 "github-updates/internal/pkg/client"
+	"github-updates/internal/pkg/configs"
+	"github-updates/internal/pkg/discussions"
+	"github-updates/internal/pkg/utils"
+	"log"
+	"time"
+)
+
+// discussionReport writes the new discussions and the answered
+// questions of every organization by category
+func discussionReport(
+	config configs.Configuration,
+	client client2.GHClientInterface,
+	repositoryLists map[string][]configs.RepositoryDetails,
+) error {
+	discussionConfig := config.Discussions
+	if !discussionConfig.DiscussionReportShouldRun {
+		return nil
+	}
+	days := discussionConfig.DiscussionCreatedHistoryDays
+	if days <= 0 {
+		days = config.GlobalConfiguration.DaysCount
+	}
+	startDate := time.Now().AddDate(0, 0, days*-1)
+
+	var discussionList []configs.DiscussionDetails
+	for _, organization := range config.GlobalConfiguration.Organizations {
+		org := organization.Organization.Github
+		repos := repositoryNames(repositoryLists[org])
+		log.Printf("Listing the discussions of %v", org)
+		repositoryDiscussions, err := client.ListDiscussions(org, repos, days)
+		if err != nil {
+			return err
+		}
+		discussionList = append(discussionList, configs.DiscussionDetails{
+			Organization: org,
+			DiscussionLists: discussions.Group(
+				repositoryDiscussions,
+				discussionConfig.DiscussionCategories,
+				startDate,
+			),
+		})
+	}
+
+	outputs :=
+		summaryOutputs(
+			utils.GetEnvOrDefault(
+				configs.DiscussionSummaryFilePath,
+				discussionConfig.DiscussionSummaryFileName,
+			),
+			summaryTemplateFile(configs.DiscussionReport),
+			discussionConfig.DiscussionOutputs,
+		)
+	err :=
+		generateReport(
+			discussionConfig.DiscussionDataFile,
+			discussionList,
+			configs.DiscussionReport,
+			outputs,
+		)
+	if err != nil {
+		return err
+	}
+	if !config.GlobalConfiguration.ExternalTemplate.Enabled {
+		return nil
+	}
+	return generateExternalDiscussion(
+		discussionConfig.DiscussionExternalTemplate,
+		getExternalDiscussions(config, discussionList),
+	)
+}
+
+// getExternalDiscussions splits the discussions by repository for
+// the external templates
+func getExternalDiscussions(
+	config configs.Configuration,
+	discussionList []configs.DiscussionDetails,
+) []configs.ExternalDiscussionDetails {
+	var externalDiscussions []configs.ExternalDiscussionDetails
+	for _, org := range discussionList {
+		organization := getOrg(
+			config.GlobalConfiguration.Organizations,
+			org.Organization,
+		)
+		for _, repo := range org.DiscussionLists {
+			externalDiscussions = append(externalDiscussions, configs.ExternalDiscussionDetails{
+				Organization: configs.OrganizationStructure{
+					Github: org.Organization,
+					Name:   organization.Name,
+				},
+				Repository: configs.RepositoryStructure{
+					Name: repo.Repository,
+					Link: "https://github.com/" + org.Organization + "/" + repo.Repository,
+				},
+				Categories: repo.Categories,
+			})
+		}
+	}
+	return externalDiscussions
+}
+
+// generateExternalDiscussion writes the external file of every
+// repository, and the summary file of all of them when it is set
+func generateExternalDiscussion(
+	externalTemplate configs.ElementExternalTemplate,
+	values []configs.ExternalDiscussionDetails,
+) error {
+	if len(values) == 0 {
+		log.Println("External template file generation is not requested")
+		return nil
+	}
+	for _, value := range values {
+		err :=
+			generateExternalFile(
+				value,
+				value.Repository.Name,
+				value.Organization.Github,
+				externalTemplate,
+			)
+		if err != nil {
+			return err
+		}
+	}
+	if externalTemplate.Summary == "" {
+		return nil
+	}
+	return generateTopFile(values, externalTemplate)
+}
@@ -0,0 +1,137 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"github-updates/internal/pkg/configs"
+	"github-updates/internal/pkg/mailer"
+	"github-updates/internal/pkg/templates"
+	"github-updates/internal/pkg/utils"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"strings"
+)
+
+// sendNewsletter mails the summaries of the reports which ran, the
+// html body is rendered with the summary templates and the plain
+// text alternative with the built-in markdown templates
+func sendNewsletter(
+	config configs.Configuration,
+	expectedPrList []configs.PullRequestDetails,
+	orgReleasesList []configs.ReleaseDetails,
+	issueList []configs.IssueDetails,
+) error {
+	emailConfig := config.Email
+	if !emailConfig.Enabled {
+		return nil
+	}
+	reports := collectedReports(config, expectedPrList, orgReleasesList, issueList)
+	kinds := emailConfig.Reports
+	if len(kinds) == 0 {
+		kinds = []string{configs.PullRequestReport, configs.ReleaseReport, configs.IssueReport}
+	}
+
+	var documents []mailer.Document
+	var texts []string
+	for _, kind := range kinds {
+		v, ok := reports[kind]
+		if !ok {
+			log.Printf("The %v report did not run, it is left out of the newsletter", kind)
+			continue
+		}
+		document, err := renderTemplate(v, configs.FormatHTML, kind, summaryTemplateFile(kind))
+		if err != nil {
+			return err
+		}
+		text, err := renderTemplate(v, configs.FormatMarkdown, kind, "")
+		if err != nil {
+			return err
+		}
+		// the linked stylesheets and the charts are relative to the summary
+		documents = append(documents, mailer.Document{
+			HTML: document,
+			Dir:  filepath.Dir(summaryFilePath(config, kind)),
+		})
+		texts = append(texts, text)
+	}
+
+	var stylesheets []string
+	for _, stylesheet := range emailConfig.Stylesheets {
+		contents, err := ioutil.ReadFile(stylesheet)
+		if err != nil {
+			return err
+		}
+		stylesheets = append(stylesheets, string(contents))
+	}
+	body, images, err := mailer.EmailHTML(documents, stylesheets)
+	if err != nil {
+		return err
+	}
+
+	port := emailConfig.Port
+	if port == 0 {
+		port = 587
+	}
+	log.Printf("Sending the newsletter to %v recipients over %v:%v", len(emailConfig.To), emailConfig.Host, port)
+	return mailer.Send(
+		mailer.Server{
+			Host:     emailConfig.Host,
+			Port:     port,
+			Username: emailConfig.Username,
+			Password: utils.GetEnvOrDefault(configs.SMTPPassword, ""),
+			StartTLS: emailConfig.StartTLS,
+		},
+		mailer.Message{
+			From:    emailConfig.From,
+			To:      emailConfig.To,
+			Subject: emailConfig.Subject,
+			HTML:    body,
+			Text:    strings.Join(texts, "\n\n"),
+			Inline:  images,
+		},
+	)
+}
+
+// summaryFilePath is the html summary of the report kind
+func summaryFilePath(config configs.Configuration, kind string) string {
+	switch kind {
+	case configs.PullRequestReport:
+		return utils.GetEnvOrDefault(configs.PrSummaryFilePath, config.PullRequests.PRSummaryFileName)
+	case configs.ReleaseReport:
+		return utils.GetEnvOrDefault(configs.ReleaseSummaryFilePath, config.Releases.ReleaseSummaryFileName)
+	case configs.IssueReport:
+		return utils.GetEnvOrDefault(configs.IssueSummaryFilePath, config.Issues.IssueSummaryFileName)
+	}
+	return ""
+}
+
+// renderTemplate executes the template of the report into a string
+func renderTemplate(v interface{}, format string, kind string, file string) (string, error) {
+	t, err := templates.Load(format, kind, file)
+	if err != nil {
+		return "", err
+	}
+	var buffer bytes.Buffer
+	err = t.Execute(&buffer, v)
+	if err != nil {
+		return "", fmt.Errorf("rendering the %v %v template: %v", kind, format, err)
+	}
+	return buffer.String(), nil
+}
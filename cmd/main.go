@@ -17,14 +17,27 @@
 package main
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
 	client2 "github-updates/internal/pkg/client"
 	"github-updates/internal/pkg/configs"
+	"github-updates/internal/pkg/contributors"
+	"github-updates/internal/pkg/export"
+	"github-updates/internal/pkg/feeds"
+	"github-updates/internal/pkg/frontmatter"
+	"github-updates/internal/pkg/metrics"
+	"github-updates/internal/pkg/schema"
+	"github-updates/internal/pkg/stale"
+	"github-updates/internal/pkg/templates"
+	"github-updates/internal/pkg/trending"
 	"github-updates/internal/pkg/utils"
+	"io/ioutil"
 	"log"
 	"os"
 	"path"
 	"path/filepath"
-	"sort"
+	"time"
 
 	"github.com/google/go-github/v33/github"
 )
@@ -46,33 +59,50 @@ func main() {
 	client := client2.NewClient()
 	log.Println("Listing repositories for each organization")
 
-	expectedPrList, orgReleasesList, issueList, errorOccurred :=
+	repositoryLists, expectedPrList, orgReleasesList, issueList, staleList, errorOccurred :=
 		getExpectedReportsLists(config, client)
 	if errorOccurred {
 		return
 	}
 	externalPRList, externalReleaseList, externalIssueList :=
 		getExternalReports(config, expectedPrList, orgReleasesList, issueList)
-	var reportFilePath, templateFilePath string
-	var err error
+	err := saveHistory(config, expectedPrList, orgReleasesList, issueList)
+	if err != nil {
+		log.Fatalf("Failed to save the history. Error is: %v", err)
+	}
 
 	if config.PullRequests.PRReportShouldRun {
 		// Save noteworthy PRs into a file
-		reportFilePath =
-			utils.GetEnvOrDefault(
-				configs.PrSummaryFilePath,
-				config.PullRequests.PRSummaryFileName,
+		outputs :=
+			summaryOutputs(
+				utils.GetEnvOrDefault(
+					configs.PrSummaryFilePath,
+					config.PullRequests.PRSummaryFileName,
+				),
+				summaryTemplateFile(configs.PullRequestReport),
+				config.PullRequests.PROutputs,
 			)
-		templateFilePath = utils.GetEnvOrDefault(configs.PRTemplateFile, "html/template/pr-template.html")
+		err = compareWithPrevious(config, config.PullRequests.PRDataFile, configs.PullRequestReport, expectedPrList)
+		if err != nil {
+			log.Fatalf("Failed to compare the pull requests with the previous run. Error is: %v", err)
+		}
+		err = drawCharts(config, outputs, expectedPrList)
+		if err != nil {
+			log.Fatalf("Failed to draw the charts of the pull requests. Error is: %v", err)
+		}
 		err =
 			generateReport(
 				config.PullRequests.PRDataFile,
 				expectedPrList,
-				reportFilePath,
-				templateFilePath,
+				configs.PullRequestReport,
+				outputs,
 			)
 		if err != nil {
-			log.Fatalf("Failed to generate the report: %v, with template: %v. Error is: %v", reportFilePath, templateFilePath, err)
+			log.Fatalf("Failed to generate the pull request report. Error is: %v", err)
+		}
+		err = cycleTimeReport(config, expectedPrList)
+		if err != nil {
+			log.Fatalf("Failed to generate the cycle time report. Error is: %v", err)
 		}
 		err =
 			generateExternalPR(
@@ -87,22 +117,37 @@ func main() {
 
 	if config.Releases.ReleaseReportShouldRun {
 		// Save releases into a file
-		reportFilePath =
-			utils.GetEnvOrDefault(
-				configs.ReleaseSummaryFilePath,
-				config.Releases.ReleaseSummaryFileName,
+		outputs :=
+			summaryOutputs(
+				utils.GetEnvOrDefault(
+					configs.ReleaseSummaryFilePath,
+					config.Releases.ReleaseSummaryFileName,
+				),
+				summaryTemplateFile(configs.ReleaseReport),
+				config.Releases.ReleaseOutputs,
 			)
-		templateFilePath = utils.GetEnvOrDefault(configs.ReleaseTemplateFile, "html/template/release-template.html")
+		err = compareWithPrevious(config, config.Releases.ReleaseDataFile, configs.ReleaseReport, orgReleasesList)
+		if err != nil {
+			log.Fatalf("Failed to compare the releases with the previous run. Error is: %v", err)
+		}
+		err = drawCharts(config, outputs, orgReleasesList)
+		if err != nil {
+			log.Fatalf("Failed to draw the charts of the releases. Error is: %v", err)
+		}
 		err =
 			generateReport(
 				config.Releases.ReleaseDataFile,
 				orgReleasesList,
-				reportFilePath,
-				templateFilePath,
+				configs.ReleaseReport,
+				outputs,
 			)
 		if err != nil {
 			log.Fatalf("Err: %v", err)
 		}
+		err = releaseTimelineReport(config, client, repositoryLists)
+		if err != nil {
+			log.Fatalf("Failed to generate the release timeline. Error is: %v", err)
+		}
 		err =
 			generateExternalRelease(
 				config.Releases.ReleaseExternalTemplate,
@@ -116,18 +161,25 @@ func main() {
 
 	if config.Issues.IssueReportShouldRun {
 		// Save releases into a file
-		reportFilePath =
-			utils.GetEnvOrDefault(
-				configs.IssueSummaryFilePath,
-				config.Issues.IssueSummaryFileName,
+		outputs :=
+			summaryOutputs(
+				utils.GetEnvOrDefault(
+					configs.IssueSummaryFilePath,
+					config.Issues.IssueSummaryFileName,
+				),
+				summaryTemplateFile(configs.IssueReport),
+				config.Issues.IssueOutputs,
 			)
-		templateFilePath = utils.GetEnvOrDefault(configs.IssueTemplateFile, "html/template/issue-template.html")
+		err = compareWithPrevious(config, config.Issues.IssueDataFile, configs.IssueReport, issueList)
+		if err != nil {
+			log.Fatalf("Failed to compare the issues with the previous run. Error is: %v", err)
+		}
 		err =
 			generateReport(
 				config.Issues.IssueDataFile,
 				issueList,
-				reportFilePath,
-				templateFilePath,
+				configs.IssueReport,
+				outputs,
 			)
 		if err != nil {
 			log.Fatalf("Err: %v", err)
@@ -142,6 +194,74 @@ func main() {
 				config.Issues.IssueExternalTemplate.Output, config.Issues.IssueExternalTemplate.Input, err)
 		}
 	}
+
+	if config.Stale.StaleReportShouldRun {
+		// Save the stuck PRs and issues into a file
+		outputs :=
+			summaryOutputs(
+				utils.GetEnvOrDefault(
+					configs.StaleSummaryFilePath,
+					config.Stale.StaleSummaryFileName,
+				),
+				summaryTemplateFile(configs.StaleReport),
+				config.Stale.StaleOutputs,
+			)
+		err =
+			generateReport(
+				config.Stale.StaleDataFile,
+				staleList,
+				configs.StaleReport,
+				outputs,
+			)
+		if err != nil {
+			log.Fatalf("Failed to generate the stale report. Error is: %v", err)
+		}
+	}
+
+	err = contributorReport(config, client, repositoryLists)
+	if err != nil {
+		log.Fatalf("Failed to generate the contributor report. Error is: %v", err)
+	}
+
+	err = scorecardReport(config, client, repositoryLists)
+	if err != nil {
+		log.Fatalf("Failed to generate the scorecard report. Error is: %v", err)
+	}
+
+	err = commitReport(config, client, repositoryLists)
+	if err != nil {
+		log.Fatalf("Failed to generate the commit report. Error is: %v", err)
+	}
+
+	err = discussionReport(config, client, repositoryLists)
+	if err != nil {
+		log.Fatalf("Failed to generate the discussion report. Error is: %v", err)
+	}
+
+	err = securityReport(config, client, repositoryLists)
+	if err != nil {
+		log.Fatalf("Failed to generate the security digest. Error is: %v", err)
+	}
+
+	err = milestoneReport(config, client, repositoryLists)
+	if err != nil {
+		log.Fatalf("Failed to generate the milestone report. Error is: %v", err)
+	}
+
+	err = buildSite(config, expectedPrList, orgReleasesList, issueList)
+	if err != nil {
+		log.Fatalf("Failed to build the site. Error is: %v", err)
+	}
+
+	err = sendNewsletter(config, expectedPrList, orgReleasesList, issueList)
+	if err != nil {
+		log.Fatalf("Failed to send the newsletter. Error is: %v", err)
+	}
+
+	err = publishDigest(config, expectedPrList, orgReleasesList, issueList)
+	if err != nil {
+		log.Fatalf("Failed to publish the digest. Error is: %v", err)
+	}
 }
 
 func getOrg(
@@ -181,10 +301,16 @@ func getExternalReports(config configs.Configuration,
 					Name:   organization.Name,
 				},
 				Repository: configs.RepositoryStructure{
-					Name: repo.Repository,
-					Link: "https://github.com/" + org.Organization + "/" + repo.Repository,
+					Name:  repo.Repository,
+					Link:  "https://github.com/" + org.Organization + "/" + repo.Repository,
+					Stars: repo.Stars,
 				},
-				PRs: repo.PRs,
+				PRs:        repo.PRs,
+				Engagement: repo.Engagement,
+			}
+			if config.PullRequests.PRNewContributors.Enabled {
+				elementPRDetails.NewContributors =
+					newContributorLogins(org, repo.Repository, config.PullRequests.PRNewContributors.Scope)
 			}
 			externalPRDetails = append(externalPRDetails, elementPRDetails)
 		}
@@ -202,8 +328,9 @@ func getExternalReports(config configs.Configuration,
 					Name:   organization.Name,
 				},
 				Repository: configs.RepositoryStructure{
-					Name: repo.Repository,
-					Link: "https://github.com/" + org.Organization + "/" + repo.Repository,
+					Name:  repo.Repository,
+					Link:  "https://github.com/" + org.Organization + "/" + repo.Repository,
+					Stars: repo.Stars,
 				},
 				Releases: repo.Releases,
 			}
@@ -223,8 +350,9 @@ func getExternalReports(config configs.Configuration,
 					Name:   organization.Name,
 				},
 				Repository: configs.RepositoryStructure{
-					Name: repo.Repository,
-					Link: "https://github.com/" + org.Organization + "/" + repo.Repository,
+					Name:  repo.Repository,
+					Link:  "https://github.com/" + org.Organization + "/" + repo.Repository,
+					Stars: repo.Stars,
 				},
 				Issues: repo.Issues,
 			}
@@ -234,6 +362,19 @@ func getExternalReports(config configs.Configuration,
 	return externalPRDetails, externalReleaseDetails, externalIssueDetails
 }
 
+// newContributorLogins lists the authors who are new to the
+// repository, with the organization scope a newcomer to the
+// organization is new to every repository of it
+func newContributorLogins(org configs.PullRequestDetails, repository string, scope string) map[string]bool {
+	logins := map[string]bool{}
+	for _, newContributor := range org.NewContributors {
+		if scope == contributors.ScopeOrganization || newContributor.Repository == repository {
+			logins[newContributor.Login] = true
+		}
+	}
+	return logins
+}
+
 func generateExternalPR(
 	externalTemplate configs.ElementExternalTemplate,
 	values []configs.ExternalPRDetails,
@@ -256,7 +397,11 @@ func generateExternalPR(
 	}
 
 	// store the trending info in summary file
-	return generateTopFile(recentPRs(values), externalTemplate)
+	top, err := trending.TopPRs(values, externalTemplate.Trending)
+	if err != nil {
+		return err
+	}
+	return generateTopFile(top, externalTemplate)
 }
 
 func generateExternalIssue(
@@ -281,7 +426,11 @@ func generateExternalIssue(
 	}
 
 	// store the trending info in summary file
-	return generateTopFile(recentIssues(values), externalTemplate)
+	top, err := trending.TopIssues(values, externalTemplate.Trending)
+	if err != nil {
+		return err
+	}
+	return generateTopFile(top, externalTemplate)
 }
 
 func generateExternalRelease(
@@ -306,7 +455,11 @@ func generateExternalRelease(
 	}
 
 	// store the trending info in summary file
-	return generateTopFile(recentReleases(values), externalTemplate)
+	top, err := trending.TopReleases(values, externalTemplate.Trending)
+	if err != nil {
+		return err
+	}
+	return generateTopFile(top, externalTemplate)
 }
 
 func generateExternalFile(
@@ -322,14 +475,70 @@ func generateExternalFile(
 	if err != nil {
 		return err
 	}
-	outputFilePath := path.Join(outputPath, outputFileName)
-	err = utils.PrettyPrint(value, outputFilePath, externalTemplate.Input)
+	if externalTemplate.FrontMatter != "" || externalTemplate.ContentConvention != "" {
+		err = generateContentFile(value, filename, outputPath, externalTemplate)
+	} else {
+		err = renderFile(value, path.Join(outputPath, outputFileName), externalTemplate.Input)
+	}
 	if err != nil {
 		return err
 	}
+	// feeds of the repository, next to the generated file
+	for _, format := range externalTemplate.Feeds {
+		err =
+			generateFeed(
+				value,
+				format,
+				"Updates from "+org+"/"+filename,
+				"https://github.com/"+org+"/"+filename,
+				path.Join(outputPath, filename+"."+feeds.Extension(format)),
+			)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// generateContentFile writes the external template output for the
+// static site generators, with the front matter and the file name
+// following the content convention
+func generateContentFile(
+	value interface{},
+	filename string,
+	outputPath string,
+	externalTemplate configs.ElementExternalTemplate,
+) error {
+	fields, err := frontmatter.New(value, time.Now())
+	if err != nil {
+		return err
+	}
+	header := ""
+	if externalTemplate.FrontMatter != "" {
+		header, err = frontmatter.Marshal(externalTemplate.FrontMatter, fields)
+		if err != nil {
+			return err
+		}
+	}
+	t, err := templates.Load("", "", externalTemplate.Input)
+	if err != nil {
+		return err
+	}
+	var body bytes.Buffer
+	err = t.Execute(&body, value)
+	if err != nil {
+		return err
+	}
+	outputFileName :=
+		frontmatter.FileName(
+			externalTemplate.ContentConvention,
+			fields,
+			filename,
+			filepath.Ext(externalTemplate.Input),
+		)
+	return ioutil.WriteFile(path.Join(outputPath, outputFileName), append([]byte(header), body.Bytes()...), 0644)
+}
+
 func generateTopFile(
 	values interface{},
 	externalTemplateInfo configs.ElementExternalTemplate,
@@ -337,54 +546,238 @@ func generateTopFile(
 	outputFileName := filepath.Base(externalTemplateInfo.Generated)
 	outputPath := filepath.Dir(externalTemplateInfo.Generated)
 	outputFilePath := path.Join(outputPath, outputFileName)
-	return utils.PrettyPrint(values, outputFilePath, externalTemplateInfo.Summary)
+	return renderFile(values, outputFilePath, externalTemplateInfo.Summary)
+}
+
+// renderFile writes the template file rendered with the value, the
+// template functions are available as in the summaries
+func renderFile(value interface{}, fileName string, templateFile string) error {
+	t, err := templates.Load("", "", templateFile)
+	if err != nil {
+		return err
+	}
+	return utils.PrettyPrintTemplate(value, fileName, t)
+}
+
+// collectedReports maps the kind of the reports which ran to
+// their data
+func collectedReports(
+	config configs.Configuration,
+	expectedPrList []configs.PullRequestDetails,
+	orgReleasesList []configs.ReleaseDetails,
+	issueList []configs.IssueDetails,
+) map[string]interface{} {
+	reports := map[string]interface{}{}
+	if config.PullRequests.PRReportShouldRun {
+		reports[configs.PullRequestReport] = expectedPrList
+	}
+	if config.Releases.ReleaseReportShouldRun {
+		reports[configs.ReleaseReport] = orgReleasesList
+	}
+	if config.Issues.IssueReportShouldRun {
+		reports[configs.IssueReport] = issueList
+	}
+	return reports
+}
+
+// summaryTemplateFile is the html template of the report kind
+func summaryTemplateFile(kind string) string {
+	switch kind {
+	case configs.PullRequestReport:
+		return utils.GetEnvOrDefault(configs.PRTemplateFile, "html/template/pr-template.html")
+	case configs.ReleaseReport:
+		return utils.GetEnvOrDefault(configs.ReleaseTemplateFile, "html/template/release-template.html")
+	case configs.IssueReport:
+		return utils.GetEnvOrDefault(configs.IssueTemplateFile, "html/template/issue-template.html")
+	case configs.ContributorReport:
+		return utils.GetEnvOrDefault(configs.ContributorTemplateFile, "html/template/contributor-template.html")
+	case configs.CycleTimeReport:
+		return utils.GetEnvOrDefault(configs.CycleTimeTemplateFile, "html/template/cycle-time-template.html")
+	case configs.StaleReport:
+		return utils.GetEnvOrDefault(configs.StaleTemplateFile, "html/template/stale-template.html")
+	case configs.ScorecardReport:
+		return utils.GetEnvOrDefault(configs.ScorecardTemplateFile, "html/template/scorecard-template.html")
+	case configs.ReleaseTimelineReport:
+		return utils.GetEnvOrDefault(configs.ReleaseTimelineTemplateFile, "html/template/release-timeline-template.html")
+	case configs.CommitReport:
+		return utils.GetEnvOrDefault(configs.CommitTemplateFile, "html/template/commit-template.html")
+	case configs.DiscussionReport:
+		return utils.GetEnvOrDefault(configs.DiscussionTemplateFile, "html/template/discussion-template.html")
+	case configs.SecurityReport:
+		return utils.GetEnvOrDefault(configs.SecurityTemplateFile, "html/template/security-template.html")
+	case configs.MilestoneReport:
+		return utils.GetEnvOrDefault(configs.MilestoneTemplateFile, "html/template/milestone-template.html")
+	}
+	return ""
+}
+
+// summaryOutputs lists every rendering of a report, the html
+// summary followed by the outputs from the configuration
+func summaryOutputs(
+	summaryFilePath string,
+	templateFilePath string,
+	outputs []configs.ReportOutput,
+) []configs.ReportOutput {
+	var summaries []configs.ReportOutput
+	if summaryFilePath != "" {
+		summaries = append(summaries, configs.ReportOutput{
+			Format:   configs.FormatHTML,
+			Path:     summaryFilePath,
+			Template: templateFilePath,
+		})
+	}
+	for _, output := range outputs {
+		if output.Format == "" {
+			output.Format = configs.FormatHTML
+		}
+		if output.Format == configs.FormatHTML && output.Template == "" {
+			output.Template = templateFilePath
+		}
+		summaries = append(summaries, output)
+	}
+	return summaries
 }
 
 func generateReport(
 	dataFileName string,
 	v interface{},
-	reportFilePath string,
-	templateFilePath string,
+	kind string,
+	outputs []configs.ReportOutput,
 ) error {
 
-	err := utils.SaveIntoFile(v, dataFileName)
+	document, err := schema.New(kind, v)
 	if err != nil {
-		log.Fatalf("Error in saving report as json : %v. Error is: %v", reportFilePath, err)
 		return err
 	}
-
-	err = utils.PrettyPrint(v, reportFilePath, templateFilePath)
+	err = utils.SaveIntoFile(document, dataFileName)
 	if err != nil {
-		log.Fatalf("Error in generating report html: %v. Error is: %v", reportFilePath, err)
+		log.Fatalf("Error in saving report as json : %v. Error is: %v", dataFileName, err)
 		return err
 	}
 
+	for _, output := range outputs {
+		err = generateOutput(v, kind, output)
+		if err != nil {
+			return fmt.Errorf("%v output %v, with template: %v: %v",
+				output.Format, output.Path, output.Template, err)
+		}
+	}
+
 	return nil
 }
 
+// generateOutput renders a single output of the report
+func generateOutput(
+	v interface{},
+	kind string,
+	output configs.ReportOutput,
+) error {
+	if output.Path == "" {
+		return errors.New("output path is not set")
+	}
+	err := os.MkdirAll(filepath.Dir(output.Path), 0755)
+	if err != nil {
+		return err
+	}
+	log.Printf("Writing the %v %v summary to %v", kind, output.Format, output.Path)
+	if feeds.IsFeed(output.Format) {
+		title := output.Title
+		if title == "" {
+			title = "Hyperledger " + kind
+		}
+		return generateFeed(v, output.Format, title, output.Link, output.Path)
+	}
+	if output.Format == configs.FormatCSV || output.Format == configs.FormatTSV {
+		return generateTable(v, kind, output)
+	}
+	t, err := templates.Load(output.Format, kind, output.Template)
+	if err != nil {
+		return err
+	}
+	return utils.PrettyPrintTemplate(v, output.Path, t)
+}
+
+// generateTable writes the flat csv or tsv export of the report
+func generateTable(
+	v interface{},
+	kind string,
+	output configs.ReportOutput,
+) error {
+	document, err := schema.New(kind, v)
+	if err != nil {
+		return err
+	}
+	separator := ','
+	if output.Format == configs.FormatTSV {
+		separator = '\t'
+	}
+	f, err := os.Create(output.Path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return export.WriteTable(f, document, output.Columns, separator)
+}
+
+// generateFeed writes the report data as an atom or rss feed
+func generateFeed(
+	v interface{},
+	format string,
+	title string,
+	link string,
+	fileName string,
+) error {
+	feed, err := feeds.NewFeed(title, link, v)
+	if err != nil {
+		return err
+	}
+	contents, err := feeds.Marshal(format, feed)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fileName, contents, 0644)
+}
+
 func getExpectedReportsLists(
 	config configs.Configuration,
 	client client2.GHClientInterface,
-) ([]configs.PullRequestDetails, []configs.ReleaseDetails, []configs.IssueDetails, bool) {
+) (
+	map[string][]configs.RepositoryDetails,
+	[]configs.PullRequestDetails,
+	[]configs.ReleaseDetails,
+	[]configs.IssueDetails,
+	[]configs.StaleDetails,
+	bool,
+) {
+	// the repositories of every organization, listed once for all the reports
+	repositoryLists := map[string][]configs.RepositoryDetails{}
 	var expectedPrList []configs.PullRequestDetails
 	var orgReleasesList []configs.ReleaseDetails
 	var issueList []configs.IssueDetails
+	var staleList []configs.StaleDetails
 
 	for _, organization := range config.GlobalConfiguration.Organizations {
 
-		repos, err := client.ListRepositories(organization.Organization.Github, config.GlobalConfiguration.RepoClass)
+		repoDetails, err := client.ListRepositoryDetails(organization.Organization.Github, config.GlobalConfiguration.RepoClass)
 		if err != nil {
 			log.Fatalf("Err: %v", err)
-			return nil, nil, nil, true
+			return nil, nil, nil, nil, nil, true
+		}
+		repositoryLists[organization.Organization.Github] = repoDetails
+		var repos []string
+		repositories := map[string]configs.RepositoryDetails{}
+		for _, repo := range repoDetails {
+			repos = append(repos, repo.Name)
+			repositories[repo.Name] = repo
 		}
 		log.Printf("List for %v is : %v", organization, repos)
 
 		if config.PullRequests.PRReportShouldRun {
 			//// Pull requests
 			expectedPrs, errorOccurred :=
-				getExpectedPullRequests(client, organization, repos, config)
+				getExpectedPullRequests(client, organization, repos, repositories, config)
 			if errorOccurred {
-				return nil, nil, nil, errorOccurred
+				return nil, nil, nil, nil, nil, errorOccurred
 			}
 			expectedPrList = append(expectedPrList, expectedPrs)
 		}
@@ -392,9 +785,9 @@ func getExpectedReportsLists(
 		if config.Releases.ReleaseReportShouldRun {
 			// Releases
 			releaseList, errorOccurred :=
-				getReleaseList(client, organization, repos, config)
+				getReleaseList(client, organization, repos, repositories, config)
 			if errorOccurred {
-				return nil, nil, nil, errorOccurred
+				return nil, nil, nil, nil, nil, errorOccurred
 			}
 			orgReleasesList = append(orgReleasesList, releaseList)
 		}
@@ -402,20 +795,58 @@ func getExpectedReportsLists(
 		if config.Issues.IssueReportShouldRun {
 			//good first issues and other configured tags
 			expectedIssues, errorOccurred :=
-				getIssueList(client, organization, repos, config)
+				getIssueList(client, organization, repos, repositories, config)
 			if errorOccurred {
-				return nil, nil, nil, errorOccurred
+				return nil, nil, nil, nil, nil, errorOccurred
 			}
 			issueList = append(issueList, expectedIssues)
 		}
+
+		if config.Stale.StaleReportShouldRun {
+			// stuck PRs and issues
+			staleDetails, errorOccurred :=
+				getStaleList(client, organization, repos, config)
+			if errorOccurred {
+				return nil, nil, nil, nil, nil, errorOccurred
+			}
+			staleList = append(staleList, staleDetails)
+		}
 	}
-	return expectedPrList, orgReleasesList, issueList, false
+	return repositoryLists, expectedPrList, orgReleasesList, issueList, staleList, false
+}
+
+func getStaleList(
+	client client2.GHClientInterface,
+	organization configs.Organization,
+	repos []string,
+	config configs.Configuration,
+) (configs.StaleDetails, bool) {
+	thresholds :=
+		stale.Thresholds(
+			config.Stale.Thresholds,
+			organization.Stale,
+			config.Issues.IssueTags,
+		)
+	staleDetails, err :=
+		stale.Find(
+			client,
+			organization.Organization.Github,
+			repos,
+			thresholds,
+			time.Now(),
+		)
+	if err != nil {
+		log.Fatalf("Err: %v", err)
+		return configs.StaleDetails{}, true
+	}
+	return staleDetails, false
 }
 
 func getReleaseList(
 	client client2.GHClientInterface,
 	organization configs.Organization,
 	repos []string,
+	repositories map[string]configs.RepositoryDetails,
 	config configs.Configuration,
 ) (configs.ReleaseDetails, bool) {
 	orgReleases, err :=
@@ -428,9 +859,16 @@ func getReleaseList(
 		log.Fatalf("Err: %v", err)
 		return configs.ReleaseDetails{}, true
 	}
+	for index := range orgReleases {
+		repository := repositories[orgReleases[index].Repository]
+		orgReleases[index].Stars = repository.Stars
+		orgReleases[index].RepositoryID = repository.ID
+	}
 	releaseList := configs.ReleaseDetails{
-		Organization:     organization.Organization.Github,
-		ReleaseRepoLists: orgReleases,
+		Organization:       organization.Organization.Github,
+		ReleaseRepoLists:   orgReleases,
+		Days:               config.GlobalConfiguration.DaysCount,
+		ListedRepositories: repos,
 	}
 	return releaseList, false
 }
@@ -439,6 +877,7 @@ func getIssueList(
 	client client2.GHClientInterface,
 	organization configs.Organization,
 	repos []string,
+	repositories map[string]configs.RepositoryDetails,
 	config configs.Configuration,
 ) (configs.IssueDetails, bool) {
 	issues, err :=
@@ -452,9 +891,16 @@ func getIssueList(
 		log.Fatalf("Err: %v", err)
 		return configs.IssueDetails{}, true
 	}
+	for index := range issues {
+		repository := repositories[issues[index].Repository]
+		issues[index].Stars = repository.Stars
+		issues[index].RepositoryID = repository.ID
+	}
 	issueList := configs.IssueDetails{
-		Organization: organization.Organization.Github,
-		IssueLists:   issues,
+		Organization:       organization.Organization.Github,
+		IssueLists:         issues,
+		Days:               config.Issues.IssueCreatedHistoryDays,
+		ListedRepositories: repos,
 	}
 	return issueList, false
 }
@@ -463,6 +909,7 @@ func getExpectedPullRequests(
 	client client2.GHClientInterface,
 	organization configs.Organization,
 	repos []string,
+	repositories map[string]configs.RepositoryDetails,
 	config configs.Configuration,
 ) (configs.PullRequestDetails, bool) {
 	pRs, err :=
@@ -475,60 +922,102 @@ func getExpectedPullRequests(
 		log.Fatalf("Err: %v", err)
 		return configs.PullRequestDetails{}, true
 	}
+	fetchEngagement :=
+		config.GlobalConfiguration.ExternalTemplate.Enabled &&
+			trending.NeedsEngagement(config.PullRequests.PRExternalTemplate.Trending)
+	for index := range pRs {
+		repository := repositories[pRs[index].Repository]
+		pRs[index].Stars = repository.Stars
+		pRs[index].RepositoryID = repository.ID
+		if !config.PullRequests.PRCycleTime.Enabled && !fetchEngagement {
+			continue
+		}
+		// the reviews serve both the cycle time and the engagement
+		reviews, err := getReviews(client, organization.Organization.Github, pRs[index])
+		if err != nil {
+			log.Fatalf("Err: %v", err)
+			return configs.PullRequestDetails{}, true
+		}
+		if config.PullRequests.PRCycleTime.Enabled {
+			pRs[index].CycleTime = getCycleTime(pRs[index], reviews)
+			summary := metrics.Summarize(pRs[index].CycleTime)
+			pRs[index].CycleTimeSummary = &summary
+		}
+		if !fetchEngagement {
+			continue
+		}
+		pRs[index].Engagement, err =
+			getEngagement(client, organization.Organization.Github, pRs[index], reviews)
+		if err != nil {
+			log.Fatalf("Err: %v", err)
+			return configs.PullRequestDetails{}, true
+		}
+	}
 	expectedPrs := configs.PullRequestDetails{
-		Organization: organization.Organization.Github,
-		PrRepoLists:  pRs,
+		Organization:       organization.Organization.Github,
+		PrRepoLists:        pRs,
+		Days:               config.GlobalConfiguration.DaysCount,
+		ListedRepositories: repos,
+	}
+	if config.PullRequests.PRNewContributors.Enabled {
+		expectedPrs.NewContributors, err =
+			contributors.NewContributors(
+				client,
+				organization.Organization.Github,
+				pRs,
+				config.PullRequests.PRNewContributors.Scope,
+			)
+		if err != nil {
+			log.Fatalf("Err: %v", err)
+			return configs.PullRequestDetails{}, true
+		}
 	}
 	return expectedPrs, false
 }
 
-func recentPRs(prs []configs.ExternalPRDetails) []github.PullRequest {
-	// get the list of all PRs from across repositories
-	var allPRs []github.PullRequest
-	for _, pr := range prs {
-		allPRs = append(allPRs, pr.PRs...)
+// getReviews fetches the reviews of every PR of the repository by
+// PR number
+func getReviews(
+	client client2.GHClientInterface,
+	org string,
+	prList configs.PrList,
+) (map[int][]github.PullRequestReview, error) {
+	reviews := map[int][]github.PullRequestReview{}
+	for _, pr := range prList.PRs {
+		prReviews, err := client.PullRequestReviews(org, prList.Repository, pr.GetNumber())
+		if err != nil {
+			return nil, err
+		}
+		reviews[pr.GetNumber()] = prReviews
 	}
-
-	// sort the PRs by top, descending order of time of creation
-	sort.Slice(allPRs, func(first, second int) bool {
-		return allPRs[first].CreatedAt.After(*allPRs[second].CreatedAt)
-	})
-
-	// return the top n items
-	// n is 5 for now
-	return allPRs[:5]
+	return reviews, nil
 }
 
-func recentIssues(issues []configs.ExternalIssueDetails) []github.Issue {
-	// get the list of all PRs from across repositories
-	var allIssues []github.Issue
-	for _, issue := range issues {
-		allIssues = append(allIssues, issue.Issues...)
+// getEngagement fetches the comments and reactions of every PR of
+// the repository, the reviews are counted from the ones fetched
+func getEngagement(
+	client client2.GHClientInterface,
+	org string,
+	prList configs.PrList,
+	reviews map[int][]github.PullRequestReview,
+) (map[int]configs.Engagement, error) {
+	engagement := map[int]configs.Engagement{}
+	for _, pr := range prList.PRs {
+		prEngagement, err := client.PullRequestEngagement(org, prList.Repository, pr.GetNumber())
+		if err != nil {
+			return nil, err
+		}
+		prEngagement.Reviews = len(reviews[pr.GetNumber()])
+		engagement[pr.GetNumber()] = prEngagement
 	}
-
-	// sort the PRs by top, descending order of time of creation
-	sort.Slice(allIssues, func(first, second int) bool {
-		return allIssues[first].CreatedAt.After(*allIssues[second].CreatedAt)
-	})
-
-	// return the top n items
-	// n is 5 for now
-	return allIssues[:5]
+	return engagement, nil
 }
 
-func recentReleases(releases []configs.ExternalReleaseDetails) []github.RepositoryRelease {
-	// get the list of all PRs from across repositories
-	var allReleases []github.RepositoryRelease
-	for _, release := range releases {
-		allReleases = append(allReleases, release.Releases...)
+// repositoryNames lists the names of the repositories
+func repositoryNames(repoDetails []configs.RepositoryDetails) []string {
+	var repos []string
+	for _, repo := range repoDetails {
+		repos = append(repos, repo.Name)
 	}
-
-	// sort the PRs by top, descending order of time of creation
-	sort.Slice(allReleases, func(first, second int) bool {
-		return allReleases[first].CreatedAt.After(allReleases[second].CreatedAt.Time)
-	})
-
-	// return the top n items
-	// n is 5 for now
-	return allReleases[:5]
+	return repos
 }
@@ -0,0 +1,114 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	client2 "github-updates/internal/pkg/client"
+	"github-updates/internal/pkg/configs"
+	"github-updates/internal/pkg/metrics"
+	"github-updates/internal/pkg/utils"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/google/go-github/v33/github"
+)
+
+// milestoneReport writes the progress of the open milestones of the
+// repositories of every organization, the repositories with no open
+// milestone are left out
+func milestoneReport(
+	config configs.Configuration,
+	client client2.GHClientInterface,
+	repositoryLists map[string][]configs.RepositoryDetails,
+) error {
+	milestoneConfig := config.Milestones
+	if !milestoneConfig.MilestoneReportShouldRun {
+		return nil
+	}
+	days := milestoneConfig.MilestoneDays
+	if days <= 0 {
+		days = config.GlobalConfiguration.DaysCount
+	}
+	now := time.Now()
+	startDate := now.AddDate(0, 0, days*-1)
+
+	var milestoneList []configs.MilestoneDetails
+	for _, organization := range config.GlobalConfiguration.Organizations {
+		org := organization.Organization.Github
+		repos := repositoryNames(repositoryLists[org])
+		milestoneDetails := configs.MilestoneDetails{Organization: org}
+		for _, repo := range repos {
+			log.Printf("Listing the milestones of %v/%v", org, repo)
+			milestones, err := client.ListMilestones(org, repo)
+			if err != nil {
+				return err
+			}
+			milestoneRepoList := configs.MilestoneList{Repository: repo}
+			for _, milestone := range milestones {
+				if !isDueWithin(milestone, milestoneConfig.DueWithinDays, now) {
+					continue
+				}
+				closedRecently, err := client.MilestoneClosedSince(org, repo, milestone.GetNumber(), startDate)
+				if err != nil {
+					return err
+				}
+				milestoneRepoList.Milestones = append(milestoneRepoList.Milestones,
+					metrics.MilestoneProgress(milestone, closedRecently, now))
+			}
+			if len(milestoneRepoList.Milestones) == 0 {
+				continue
+			}
+			// the first due first, the ones with no due date last
+			sort.SliceStable(milestoneRepoList.Milestones, func(i, j int) bool {
+				first, second := milestoneRepoList.Milestones[i].DueOn, milestoneRepoList.Milestones[j].DueOn
+				if first == nil || second == nil {
+					return second == nil && first != nil
+				}
+				return first.Before(*second)
+			})
+			milestoneDetails.MilestoneLists = append(milestoneDetails.MilestoneLists, milestoneRepoList)
+		}
+		milestoneList = append(milestoneList, milestoneDetails)
+	}
+
+	outputs :=
+		summaryOutputs(
+			utils.GetEnvOrDefault(
+				configs.MilestoneSummaryFilePath,
+				milestoneConfig.MilestoneSummaryFileName,
+			),
+			summaryTemplateFile(configs.MilestoneReport),
+			milestoneConfig.MilestoneOutputs,
+		)
+	return generateReport(
+		milestoneConfig.MilestoneDataFile,
+		milestoneList,
+		configs.MilestoneReport,
+		outputs,
+	)
+}
+
+// isDueWithin tells if the milestone is due within the days, the
+// overdue ones included. Every milestone is when the days are 0,
+// else the ones with no due date are left out.
+func isDueWithin(milestone github.Milestone, days int, now time.Time) bool {
+	if days <= 0 {
+		return true
+	}
+	return milestone.DueOn != nil && milestone.GetDueOn().Before(now.AddDate(0, 0, days))
+}
@@ -0,0 +1,111 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"github-updates/internal/pkg/configs"
+	"github-updates/internal/pkg/publishers"
+	"github-updates/internal/pkg/utils"
+	"log"
+	"time"
+)
+
+// publishDigest posts the short summary of the run to the chat
+// channels which are enabled
+func publishDigest(
+	config configs.Configuration,
+	expectedPrList []configs.PullRequestDetails,
+	orgReleasesList []configs.ReleaseDetails,
+	issueList []configs.IssueDetails,
+) error {
+	publisherConfig := config.Publishers
+	var webhooks []configs.WebhookConfiguration
+	for _, webhook := range publisherConfig.Webhooks {
+		if webhook.Enabled {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	if publisherConfig.Mastodon.Enabled && config.Releases.ReleaseReportShouldRun {
+		err := announceReleases(publisherConfig.Mastodon, orgReleasesList, config.GlobalConfiguration.DaysCount)
+		if err != nil {
+			return err
+		}
+	}
+	if !publisherConfig.Slack.Enabled && !publisherConfig.Discord.Enabled && len(webhooks) == 0 {
+		return nil
+	}
+	since := time.Now().AddDate(0, 0, -config.GlobalConfiguration.DaysCount)
+	digest :=
+		publishers.NewDigest(
+			publisherConfig.Digest,
+			since,
+			expectedPrList,
+			orgReleasesList,
+			issueList,
+		)
+	if digest.IsEmpty() {
+		log.Println("Nothing to publish in the digest")
+		return nil
+	}
+	title := publisherConfig.Title
+	if title == "" {
+		title = "Hyperledger weekly updates"
+	}
+
+	if publisherConfig.Slack.Enabled {
+		log.Println("Publishing the digest to Slack")
+		webhookURL := utils.GetEnvOrDefault(configs.SlackWebhookURL, publisherConfig.Slack.WebhookURL)
+		err := publishers.PublishSlack(webhookURL, title, digest)
+		if err != nil {
+			return err
+		}
+	}
+	if publisherConfig.Discord.Enabled {
+		log.Println("Publishing the digest to Discord")
+		webhookURL := utils.GetEnvOrDefault(configs.DiscordWebhookURL, publisherConfig.Discord.WebhookURL)
+		err := publishers.PublishDiscord(webhookURL, title, digest)
+		if err != nil {
+			return err
+		}
+	}
+	for _, webhook := range webhooks {
+		log.Printf("Publishing the digest to the %v webhook", webhook.Name)
+		err := publishers.PublishWebhook(webhook, title, digest)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// announceReleases posts the releases not announced yet to Mastodon
+func announceReleases(
+	mastodon configs.MastodonConfiguration,
+	orgReleasesList []configs.ReleaseDetails,
+	days int,
+) error {
+	if mastodon.StateFile == "" {
+		mastodon.StateFile = "generated-data/mastodon-state.json"
+	}
+	log.Printf("Announcing the releases on %v", mastodon.Instance)
+	return publishers.PublishMastodon(
+		mastodon,
+		utils.GetEnvOrDefault(configs.MastodonAccessToken, ""),
+		publishers.Announcements(orgReleasesList),
+		days,
+	)
+}
@@ -0,0 +1,88 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	client2 "github-updates/internal/pkg/client"
+	"github-updates/internal/pkg/configs"
+	"github-updates/internal/pkg/metrics"
+	"github-updates/internal/pkg/utils"
+	"log"
+	"sort"
+	"time"
+)
+
+// scorecardReport writes the health scorecard of the repositories
+// of every organization, the archived ones are left out
+func scorecardReport(
+	config configs.Configuration,
+	client client2.GHClientInterface,
+	repositoryLists map[string][]configs.RepositoryDetails,
+) error {
+	scorecardConfig := config.Scorecard
+	if !scorecardConfig.ScorecardReportShouldRun {
+		return nil
+	}
+	days := scorecardConfig.Days
+	if days <= 0 {
+		days = 90
+	}
+	now := time.Now()
+
+	var healthList []configs.HealthDetails
+	for _, organization := range config.GlobalConfiguration.Organizations {
+		org := organization.Organization.Github
+		repoDetails := repositoryLists[org]
+		healthDetails := configs.HealthDetails{Organization: org}
+		for _, repo := range repoDetails {
+			if repo.Archived {
+				continue
+			}
+			log.Printf("Gathering the health signals of %v/%v", org, repo.Name)
+			signals, err := client.RepositoryHealth(org, repo, days)
+			if err != nil {
+				return err
+			}
+			healthDetails.Repositories = append(healthDetails.Repositories, configs.RepositoryHealth{
+				Repository: repo.Name,
+				URL:        "https://github.com/" + org + "/" + repo.Name,
+				Signals:    signals,
+				Score:      metrics.Score(signals, scorecardConfig.Weights, scorecardConfig.Thresholds, now),
+			})
+		}
+		sort.SliceStable(healthDetails.Repositories, func(i, j int) bool {
+			return healthDetails.Repositories[i].Score.Total > healthDetails.Repositories[j].Score.Total
+		})
+		healthList = append(healthList, healthDetails)
+	}
+
+	outputs :=
+		summaryOutputs(
+			utils.GetEnvOrDefault(
+				configs.ScorecardSummaryFilePath,
+				scorecardConfig.ScorecardSummaryFileName,
+			),
+			summaryTemplateFile(configs.ScorecardReport),
+			scorecardConfig.ScorecardOutputs,
+		)
+	return generateReport(
+		scorecardConfig.ScorecardDataFile,
+		healthList,
+		configs.ScorecardReport,
+		outputs,
+	)
+}
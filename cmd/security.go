@@ -0,0 +1,81 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	client2 "github-updates/internal/pkg/client"
+	"github-updates/internal/pkg/configs"
+	"github-updates/internal/pkg/security"
+	"github-updates/internal/pkg/utils"
+	"log"
+)
+
+// securityReport writes the digest of the security advisories and
+// the vulnerability fixes of every organization
+func securityReport(
+	config configs.Configuration,
+	client client2.GHClientInterface,
+	repositoryLists map[string][]configs.RepositoryDetails,
+) error {
+	securityConfig := config.Security
+	if !securityConfig.SecurityReportShouldRun {
+		return nil
+	}
+	days := securityConfig.SecurityDays
+	if days <= 0 {
+		days = config.GlobalConfiguration.DaysCount
+	}
+
+	var securityList []configs.SecurityDetails
+	for _, organization := range config.GlobalConfiguration.Organizations {
+		org := organization.Organization.Github
+		repos := repositoryNames(repositoryLists[org])
+		log.Printf("Listing the security advisories of %v", org)
+		advisoryLists, err := client.ListSecurityAdvisories(org, repos, days)
+		if err != nil {
+			return err
+		}
+		releaseLists, err := client.ListReleases(org, repos, days)
+		if err != nil {
+			return err
+		}
+		prLists, err := client.ListPRs(org, repos, days)
+		if err != nil {
+			return err
+		}
+		securityList = append(securityList, configs.SecurityDetails{
+			Organization:  org,
+			SecurityLists: security.Digest(advisoryLists, releaseLists, prLists, securityConfig),
+		})
+	}
+
+	outputs :=
+		summaryOutputs(
+			utils.GetEnvOrDefault(
+				configs.SecuritySummaryFilePath,
+				securityConfig.SecuritySummaryFileName,
+			),
+			summaryTemplateFile(configs.SecurityReport),
+			securityConfig.SecurityOutputs,
+		)
+	return generateReport(
+		securityConfig.SecurityDataFile,
+		securityList,
+		configs.SecurityReport,
+		outputs,
+	)
+}
@@ -0,0 +1,56 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"github-updates/internal/pkg/configs"
+	"github-updates/internal/pkg/schema"
+	"github-updates/internal/pkg/site"
+	"log"
+	"time"
+)
+
+// buildSite adds the issue of the week to the static site
+func buildSite(
+	config configs.Configuration,
+	expectedPrList []configs.PullRequestDetails,
+	orgReleasesList []configs.ReleaseDetails,
+	issueList []configs.IssueDetails,
+) error {
+	siteConfig := config.Site
+	if !siteConfig.Enabled {
+		return nil
+	}
+	if siteConfig.Root == "" {
+		siteConfig.Root = "site"
+	}
+	if siteConfig.Title == "" {
+		siteConfig.Title = "Hyperledger Updates"
+	}
+
+	reports := collectedReports(config, expectedPrList, orgReleasesList, issueList)
+	var documents []schema.Document
+	for kind, v := range reports {
+		document, err := schema.New(kind, v)
+		if err != nil {
+			return err
+		}
+		documents = append(documents, document)
+	}
+	log.Printf("Building the newsletter site in %v", siteConfig.Root)
+	return site.Build(siteConfig, time.Now(), documents)
+}
@@ -0,0 +1,63 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"github-updates/internal/pkg/configs"
+	"github-updates/internal/pkg/schema"
+	"github-updates/internal/pkg/storage"
+	"log"
+)
+
+// saveHistory upserts the collected activity into the SQLite store
+func saveHistory(
+	config configs.Configuration,
+	expectedPrList []configs.PullRequestDetails,
+	orgReleasesList []configs.ReleaseDetails,
+	issueList []configs.IssueDetails,
+) error {
+	sqliteConfig := config.GlobalConfiguration.Storage.SQLite
+	if !sqliteConfig.Enabled {
+		return nil
+	}
+	log.Printf("Saving the activity history into %v", sqliteConfig.Path)
+	store, err := storage.Open(sqliteConfig.Path)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	for _, organization := range config.GlobalConfiguration.Organizations {
+		err = store.SaveOrganization(organization.Organization)
+		if err != nil {
+			return err
+		}
+	}
+
+	reports := collectedReports(config, expectedPrList, orgReleasesList, issueList)
+	for kind, v := range reports {
+		document, err := schema.New(kind, v)
+		if err != nil {
+			return err
+		}
+		err = store.SaveDocument(document)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	client2 "github-updates/internal/pkg/client"
+	"github-updates/internal/pkg/configs"
+	"github-updates/internal/pkg/metrics"
+	"github-updates/internal/pkg/utils"
+	"log"
+	"sort"
+	"time"
+)
+
+// releaseTimelineReport writes the release cadence of the
+// repositories of every organization over their whole history
+func releaseTimelineReport(
+	config configs.Configuration,
+	client client2.GHClientInterface,
+	repositoryLists map[string][]configs.RepositoryDetails,
+) error {
+	timelineConfig := config.Releases.ReleaseTimeline
+	if !timelineConfig.Enabled {
+		return nil
+	}
+	now := time.Now()
+
+	var timelineList []configs.TimelineDetails
+	for _, organization := range config.GlobalConfiguration.Organizations {
+		org := organization.Organization.Github
+		repos := repositoryNames(repositoryLists[org])
+		timelineDetails := configs.TimelineDetails{
+			Organization: org,
+			SLADays:      timelineConfig.SLADays,
+		}
+		for _, repo := range repos {
+			log.Printf("Listing the release history of %v/%v", org, repo)
+			releases, err := client.ListAllReleases(org, repo)
+			if err != nil {
+				return err
+			}
+			timelineDetails.Repositories = append(timelineDetails.Repositories,
+				metrics.Cadence(org, repo, releases, timelineConfig, now))
+		}
+		// the projects over the SLA first, then the longest silent
+		sort.SliceStable(timelineDetails.Repositories, func(i, j int) bool {
+			first, second := timelineDetails.Repositories[i], timelineDetails.Repositories[j]
+			if first.OverSLA != second.OverSLA {
+				return first.OverSLA
+			}
+			return daysSinceLast(first) > daysSinceLast(second)
+		})
+		timelineDetails.Since = metrics.PlaceTimeline(timelineDetails.Repositories, now)
+		timelineList = append(timelineList, timelineDetails)
+	}
+
+	outputs :=
+		summaryOutputs(
+			utils.GetEnvOrDefault(
+				configs.ReleaseTimelineSummaryFilePath,
+				timelineConfig.SummaryFileName,
+			),
+			summaryTemplateFile(configs.ReleaseTimelineReport),
+			timelineConfig.Outputs,
+		)
+	return generateReport(
+		timelineConfig.DataFile,
+		timelineList,
+		configs.ReleaseTimelineReport,
+		outputs,
+	)
+}
+
+// daysSinceLast sorts the repositories which never released last
+func daysSinceLast(timeline configs.RepositoryTimeline) int {
+	if timeline.DaysSinceLast == nil {
+		return -1
+	}
+	return *timeline.DaysSinceLast
+}
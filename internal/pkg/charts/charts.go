@@ -0,0 +1,206 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package charts draws the simple SVG charts of the reports, with
+// no external renderer, so that the summaries get graphs without a
+// charting library
+package charts
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"strings"
+	"time"
+)
+
+const (
+	fontSize   = 12
+	padding    = 8
+	titleSpace = 20
+	barColor   = "#2b7bb9"
+	mutedColor = "#999999"
+	textColor  = "#333333"
+)
+
+// Bar is a labelled value of the bar chart
+type Bar struct {
+	Label string
+	Value float64
+}
+
+// TimelineRow is a labelled lane of the timeline with its dates
+type TimelineRow struct {
+	Label string
+	Dates []time.Time
+}
+
+// BarChart draws horizontal bars, one per value, in the given order.
+// The bars are scaled to the largest value.
+func BarChart(title string, bars []Bar) string {
+	const (
+		width    = 480
+		barSpace = 22
+		barSize  = 16
+	)
+	labelWidth := labelsWidth(bars)
+	height := titleSpace + len(bars)*barSpace + padding*2
+	maxValue := 0.0
+	for _, bar := range bars {
+		maxValue = math.Max(maxValue, bar.Value)
+	}
+	// keep room for the value after the longest bar
+	scale := float64(width-labelWidth-padding*2-40) / math.Max(maxValue, 1)
+
+	var svg strings.Builder
+	open(&svg, title, width, height)
+	for index, bar := range bars {
+		y := padding + titleSpace + index*barSpace
+		fmt.Fprintf(&svg,
+			`<text x="%d" y="%d" text-anchor="end">%s</text>`+"\n",
+			padding+labelWidth, y+barSize-4, html.EscapeString(bar.Label))
+		length := bar.Value * scale
+		fmt.Fprintf(&svg,
+			`<rect x="%d" y="%d" width="%.1f" height="%d" fill="%s"/>`+"\n",
+			padding*2+labelWidth, y, length, barSize, barColor)
+		fmt.Fprintf(&svg,
+			`<text x="%.1f" y="%d">%v</text>`+"\n",
+			float64(padding*3+labelWidth)+length, y+barSize-4, bar.Value)
+	}
+	svg.WriteString("</svg>\n")
+	return svg.String()
+}
+
+// Sparkline draws the values as a line, the oldest first, with the
+// last value marked and printed
+func Sparkline(title string, values []int) string {
+	const (
+		width  = 240
+		height = 60
+	)
+	var svg strings.Builder
+	open(&svg, title, width, height)
+	top := padding + titleSpace
+	bottom := height - padding
+	right := width - padding - 30
+	maxValue := 1
+	for _, value := range values {
+		if value > maxValue {
+			maxValue = value
+		}
+	}
+	x := func(index int) float64 {
+		if len(values) < 2 {
+			return float64(right)
+		}
+		return float64(padding) + float64(index)*float64(right-padding)/float64(len(values)-1)
+	}
+	y := func(value int) float64 {
+		return float64(bottom) - float64(value)*float64(bottom-top)/float64(maxValue)
+	}
+	if len(values) > 0 {
+		points := make([]string, len(values))
+		for index, value := range values {
+			points[index] = fmt.Sprintf("%.1f,%.1f", x(index), y(value))
+		}
+		fmt.Fprintf(&svg,
+			`<polyline points="%s" fill="none" stroke="%s" stroke-width="2"/>`+"\n",
+			strings.Join(points, " "), barColor)
+		last := len(values) - 1
+		fmt.Fprintf(&svg,
+			`<circle cx="%.1f" cy="%.1f" r="3" fill="%s"/>`+"\n",
+			x(last), y(values[last]), barColor)
+		fmt.Fprintf(&svg,
+			`<text x="%.1f" y="%.1f">%d</text>`+"\n",
+			x(last)+6, y(values[last])+4, values[last])
+	}
+	svg.WriteString("</svg>\n")
+	return svg.String()
+}
+
+// Timeline draws a lane per row with a mark at each date, between
+// the start and the end
+func Timeline(title string, rows []TimelineRow, start time.Time, end time.Time) string {
+	const (
+		width     = 480
+		laneSpace = 22
+	)
+	bars := make([]Bar, len(rows))
+	for index, row := range rows {
+		bars[index].Label = row.Label
+	}
+	labelWidth := labelsWidth(bars)
+	height := titleSpace + len(rows)*laneSpace + padding*2 + fontSize + 4
+	left := float64(padding*2 + labelWidth)
+	right := float64(width - padding*2)
+	span := end.Sub(start)
+	x := func(date time.Time) float64 {
+		if span <= 0 {
+			return right
+		}
+		return left + float64(date.Sub(start))/float64(span)*(right-left)
+	}
+
+	var svg strings.Builder
+	open(&svg, title, width, height)
+	for index, row := range rows {
+		y := padding + titleSpace + index*laneSpace + laneSpace/2
+		fmt.Fprintf(&svg,
+			`<text x="%d" y="%d" text-anchor="end">%s</text>`+"\n",
+			padding+labelWidth, y+4, html.EscapeString(row.Label))
+		fmt.Fprintf(&svg,
+			`<line x1="%.1f" y1="%d" x2="%.1f" y2="%d" stroke="%s"/>`+"\n",
+			left, y, right, y, mutedColor)
+		for _, date := range row.Dates {
+			fmt.Fprintf(&svg,
+				`<circle cx="%.1f" cy="%d" r="4" fill="%s"><title>%s</title></circle>`+"\n",
+				x(date), y, barColor, date.Format("2006-01-02"))
+		}
+	}
+	axis := height - padding
+	fmt.Fprintf(&svg,
+		`<text x="%.1f" y="%d" fill="%s">%s</text>`+"\n",
+		left, axis, mutedColor, start.Format("2006-01-02"))
+	fmt.Fprintf(&svg,
+		`<text x="%.1f" y="%d" fill="%s" text-anchor="end">%s</text>`+"\n",
+		right, axis, mutedColor, end.Format("2006-01-02"))
+	svg.WriteString("</svg>\n")
+	return svg.String()
+}
+
+// open writes the svg element with the title of the chart
+func open(svg *strings.Builder, title string, width int, height int) {
+	fmt.Fprintf(svg,
+		`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" `+
+			`font-family="sans-serif" font-size="%d" fill="%s">`+"\n",
+		width, height, width, height, fontSize, textColor)
+	fmt.Fprintf(svg, "<title>%s</title>\n", html.EscapeString(title))
+	fmt.Fprintf(svg,
+		`<text x="%d" y="%d" font-weight="bold">%s</text>`+"\n",
+		padding, padding+fontSize, html.EscapeString(title))
+}
+
+// labelsWidth estimates the width of the longest label, the
+// characters of the font are about 0.6 of its size
+func labelsWidth(bars []Bar) int {
+	longest := 0
+	for _, bar := range bars {
+		if length := len([]rune(bar.Label)); length > longest {
+			longest = length
+		}
+	}
+	return int(math.Ceil(float64(longest*fontSize) * 0.6))
+}
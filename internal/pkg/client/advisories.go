@@ -0,0 +1,110 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"fmt"
+	"github-updates/internal/pkg/configs"
+	"net/http"
+	"time"
+
+	"github.com/google/go-github/v33/github"
+)
+
+// repositoryAdvisory is a security advisory of the REST API, which
+// go-github v33 does not know about
+type repositoryAdvisory struct {
+	GHSAID          string    `json:"ghsa_id"`
+	CVEID           string    `json:"cve_id"`
+	HTMLURL         string    `json:"html_url"`
+	Summary         string    `json:"summary"`
+	Severity        string    `json:"severity"`
+	PublishedAt     time.Time `json:"published_at"`
+	Vulnerabilities []struct {
+		Package struct {
+			Ecosystem string `json:"ecosystem"`
+			Name      string `json:"name"`
+		} `json:"package"`
+		VulnerableVersionRange string `json:"vulnerable_version_range"`
+		PatchedVersions        string `json:"patched_versions"`
+	} `json:"vulnerabilities"`
+}
+
+// ListSecurityAdvisories returns the security advisories published
+// for the repositories during the days, the newest first. Only the
+// repositories with an advisory are listed.
+func (c Client) ListSecurityAdvisories(org string, repos []string, daysCount int) ([]configs.SecurityList, error) {
+	startDate := time.Now().AddDate(0, 0, daysCount*-1)
+	var securityLists []configs.SecurityList
+
+	for _, repo := range repos {
+		securityList := configs.SecurityList{Repository: repo}
+		listOption := &github.ListOptions{
+			PerPage: 100,
+		}
+		dateReached := false
+		for !dateReached {
+			url := fmt.Sprintf(
+				"repos/%v/%v/security-advisories?state=published&sort=published&direction=desc&per_page=%v&page=%v",
+				org, repo, listOption.PerPage, listOption.Page)
+			request, err := c.Client.NewRequest("GET", url, nil)
+			if err != nil {
+				return nil, err
+			}
+			var advisories []repositoryAdvisory
+			response, err := c.Client.Do(c.Context, request, &advisories)
+			if err != nil {
+				// the advisories are not visible for every repository
+				if response != nil && response.StatusCode == http.StatusNotFound {
+					break
+				}
+				return nil, err
+			}
+			for _, advisory := range advisories {
+				if advisory.PublishedAt.Before(startDate) {
+					dateReached = true
+					break
+				}
+				securityAdvisory := configs.Advisory{
+					GHSAID:      advisory.GHSAID,
+					CVEID:       advisory.CVEID,
+					Summary:     advisory.Summary,
+					URL:         advisory.HTMLURL,
+					Severity:    advisory.Severity,
+					PublishedAt: advisory.PublishedAt,
+				}
+				for _, vulnerability := range advisory.Vulnerabilities {
+					securityAdvisory.Vulnerabilities = append(securityAdvisory.Vulnerabilities, configs.AdvisoryVulnerability{
+						Ecosystem:        vulnerability.Package.Ecosystem,
+						Package:          vulnerability.Package.Name,
+						AffectedVersions: vulnerability.VulnerableVersionRange,
+						PatchedVersions:  vulnerability.PatchedVersions,
+					})
+				}
+				securityList.Advisories = append(securityList.Advisories, securityAdvisory)
+			}
+			if response.NextPage == 0 {
+				break
+			}
+			listOption.Page = response.NextPage
+		}
+		if len(securityList.Advisories) != 0 {
+			securityLists = append(securityLists, securityList)
+		}
+	}
+	return securityLists, nil
+}
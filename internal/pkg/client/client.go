@@ -16,12 +16,32 @@
 
 package client
 
-import "github-updates/internal/pkg/configs"
+import (
+	"github-updates/internal/pkg/configs"
+	"time"
+
+	"github.com/google/go-github/v33/github"
+)
 
 // GHClientInterface is for testing
 type GHClientInterface interface {
 	ListRepositories(string, string) ([]string, error)
+	ListRepositoryDetails(string, string) ([]configs.RepositoryDetails, error)
+	PullRequestEngagement(string, string, int) (configs.Engagement, error)
+	PullRequestReviews(string, string, int) ([]github.PullRequestReview, error)
+	MergedPRsBefore(string, string, string, time.Time) (int, error)
 	ListPRs(string, []string, int) ([]configs.PrList, error)
 	ListReleases(string, []string, int) ([]configs.ReleaseList, error)
 	IssueWithLabels(string, []string, []string, int) ([]configs.IssueList, error)
+	ListActivity(string, []string, int) ([]configs.ActivityList, error)
+	ListOpenPRs(string, []string) ([]configs.PrList, error)
+	ListAssignedIssues(string, []string, []string) ([]configs.IssueList, error)
+	AssigneeLastActivity(string, string, int, []string) (time.Time, error)
+	RepositoryHealth(string, configs.RepositoryDetails, int) (configs.HealthSignals, error)
+	ListAllReleases(string, string) ([]github.RepositoryRelease, error)
+	ListCommits(string, configs.RepositoryDetails, int, bool) ([]github.RepositoryCommit, error)
+	ListDiscussions(string, []string, int) ([]configs.RepositoryDiscussions, error)
+	ListSecurityAdvisories(string, []string, int) ([]configs.SecurityList, error)
+	ListMilestones(string, string) ([]github.Milestone, error)
+	MilestoneClosedSince(string, string, int, time.Time) (int, error)
 }
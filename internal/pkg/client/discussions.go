@@ -0,0 +1,198 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"github-updates/internal/pkg/configs"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// discussionsQuery lists the discussions of a repository, the most
+// recently updated first. The REST API has no discussions.
+const discussionsQuery = `
+query($owner: String!, $name: String!, $cursor: String) {
+  repository(owner: $owner, name: $name) {
+    hasDiscussionsEnabled
+    discussions(first: 50, after: $cursor, orderBy: {field: UPDATED_AT, direction: DESC}) {
+      pageInfo { hasNextPage endCursor }
+      nodes {
+        number
+        title
+        url
+        createdAt
+        updatedAt
+        upvoteCount
+        answerChosenAt
+        author { login url avatarUrl }
+        category { name emoji isAnswerable }
+        comments { totalCount }
+        answer { url author { login url } }
+      }
+    }
+  }
+}`
+
+type graphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+type graphQLError struct {
+	Message string `json:"message"`
+}
+
+type graphQLActor struct {
+	Login     string `json:"login"`
+	URL       string `json:"url"`
+	AvatarURL string `json:"avatarUrl"`
+}
+
+type discussionsResponse struct {
+	Data struct {
+		Repository *struct {
+			HasDiscussionsEnabled bool `json:"hasDiscussionsEnabled"`
+			Discussions           struct {
+				PageInfo struct {
+					HasNextPage bool   `json:"hasNextPage"`
+					EndCursor   string `json:"endCursor"`
+				} `json:"pageInfo"`
+				Nodes []struct {
+					Number         int           `json:"number"`
+					Title          string        `json:"title"`
+					URL            string        `json:"url"`
+					CreatedAt      time.Time     `json:"createdAt"`
+					UpdatedAt      time.Time     `json:"updatedAt"`
+					UpvoteCount    int           `json:"upvoteCount"`
+					AnswerChosenAt *time.Time    `json:"answerChosenAt"`
+					Author         *graphQLActor `json:"author"`
+					Category       struct {
+						Name         string `json:"name"`
+						Emoji        string `json:"emoji"`
+						IsAnswerable bool   `json:"isAnswerable"`
+					} `json:"category"`
+					Comments struct {
+						TotalCount int `json:"totalCount"`
+					} `json:"comments"`
+					Answer *struct {
+						URL    string        `json:"url"`
+						Author *graphQLActor `json:"author"`
+					} `json:"answer"`
+				} `json:"nodes"`
+			} `json:"discussions"`
+		} `json:"repository"`
+	} `json:"data"`
+	Errors []graphQLError `json:"errors"`
+}
+
+// ListDiscussions returns the discussions of the repositories which
+// were updated during the days, the repositories with the discussions
+// disabled are left out
+func (c Client) ListDiscussions(org string, repos []string, daysCount int) ([]configs.RepositoryDiscussions, error) {
+	startDate := time.Now().AddDate(0, 0, daysCount*-1)
+	var discussionLists []configs.RepositoryDiscussions
+
+	for _, repo := range repos {
+		repositoryDiscussions := configs.RepositoryDiscussions{Repository: repo}
+		variables := map[string]interface{}{"owner": org, "name": repo, "cursor": nil}
+		dateReached := false
+		enabled := true
+		for !dateReached {
+			var response discussionsResponse
+			err := c.graphQL(discussionsQuery, variables, &response)
+			if err != nil {
+				return nil, err
+			}
+			if len(response.Errors) != 0 {
+				var messages []string
+				for _, graphQLErr := range response.Errors {
+					messages = append(messages, graphQLErr.Message)
+				}
+				return nil, fmt.Errorf("listing the discussions of %v/%v: %v", org, repo, strings.Join(messages, ", "))
+			}
+			repository := response.Data.Repository
+			if repository == nil || !repository.HasDiscussionsEnabled {
+				enabled = false
+				break
+			}
+			for _, node := range repository.Discussions.Nodes {
+				if node.UpdatedAt.Before(startDate) {
+					dateReached = true
+					break
+				}
+				discussion := configs.Discussion{
+					Number:         node.Number,
+					Title:          node.Title,
+					URL:            node.URL,
+					Category:       node.Category.Name,
+					CategoryEmoji:  node.Category.Emoji,
+					Answerable:     node.Category.IsAnswerable,
+					CreatedAt:      node.CreatedAt,
+					UpdatedAt:      node.UpdatedAt,
+					Comments:       node.Comments.TotalCount,
+					Upvotes:        node.UpvoteCount,
+					AnswerChosenAt: node.AnswerChosenAt,
+				}
+				// the author of a deleted account is null
+				if node.Author != nil {
+					discussion.Author = node.Author.Login
+					discussion.AuthorURL = node.Author.URL
+					discussion.AuthorAvatarURL = node.Author.AvatarURL
+				}
+				if node.Answer != nil {
+					discussion.AnswerURL = node.Answer.URL
+					if node.Answer.Author != nil {
+						discussion.AnsweredBy = node.Answer.Author.Login
+						discussion.AnsweredByURL = node.Answer.Author.URL
+					}
+				}
+				repositoryDiscussions.Discussions = append(repositoryDiscussions.Discussions, discussion)
+			}
+			if !repository.Discussions.PageInfo.HasNextPage {
+				break
+			}
+			variables["cursor"] = repository.Discussions.PageInfo.EndCursor
+		}
+		if !enabled {
+			log.Printf("Discussions are not enabled in %v/%v", org, repo)
+			continue
+		}
+		discussionLists = append(discussionLists, repositoryDiscussions)
+	}
+	return discussionLists, nil
+}
+
+// graphQL posts the query to the GraphQL API of GitHub, which only
+// answers the authenticated requests
+func (c Client) graphQL(query string, variables map[string]interface{}, v interface{}) error {
+	request, err := c.Client.NewRequest("POST", "graphql", graphQLRequest{
+		Query:     query,
+		Variables: variables,
+	})
+	if err != nil {
+		return err
+	}
+	response, err := c.Client.Do(c.Context, request, v)
+	if response != nil && response.StatusCode == http.StatusUnauthorized {
+		return errors.New("the GraphQL API needs the " + configs.GitHubToken)
+	}
+	return err
+}
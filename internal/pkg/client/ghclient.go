@@ -19,10 +19,12 @@ package client
 import (
 	ctx "context"
 	"errors"
+	"fmt"
 	"github-updates/internal/pkg/configs"
 	"github-updates/internal/pkg/utils"
 	"log"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/google/go-github/v33/github"
@@ -33,6 +35,8 @@ import (
 type Client struct {
 	Client  *github.Client
 	Context ctx.Context
+	// mergedPRs keeps the search results of MergedPRsBefore
+	mergedPRs *mergedPRCache
 }
 
 // NewClient creates a new instance of GitHub client
@@ -41,22 +45,37 @@ func NewClient() GHClientInterface {
 	context := ctx.Background()
 	if token == "" {
 		return Client{
-			Client:  github.NewClient(nil),
-			Context: context,
+			Client:    github.NewClient(nil),
+			Context:   context,
+			mergedPRs: newMergedPRCache(),
 		}
 	}
 	oauth2Client := oauth2.NewClient(context, oauth2.StaticTokenSource(
 		&oauth2.Token{AccessToken: token},
 	))
 	return Client{
-		Client:  github.NewClient(oauth2Client),
-		Context: context,
+		Client:    github.NewClient(oauth2Client),
+		Context:   context,
+		mergedPRs: newMergedPRCache(),
 	}
 }
 
 // ListRepositories returns the list of all repositories
 func (c Client) ListRepositories(org string, repoClass string) ([]string, error) {
+	repositories, err := c.ListRepositoryDetails(org, repoClass)
+	if err != nil {
+		return nil, err
+	}
 	var listOfRepositories []string
+	for _, repository := range repositories {
+		listOfRepositories = append(listOfRepositories, repository.Name)
+	}
+	return listOfRepositories, nil
+}
+
+// ListRepositoryDetails returns all repositories with their metadata
+func (c Client) ListRepositoryDetails(org string, repoClass string) ([]configs.RepositoryDetails, error) {
+	var listOfRepositories []configs.RepositoryDetails
 	listOption := &github.RepositoryListByOrgOptions{
 		ListOptions: github.ListOptions{
 			PerPage: 20,
@@ -74,7 +93,13 @@ func (c Client) ListRepositories(org string, repoClass string) ([]string, error)
 			return nil, errors.New("could not get the response")
 		}
 		for _, repository := range repositories {
-			listOfRepositories = append(listOfRepositories, *repository.Name)
+			listOfRepositories = append(listOfRepositories, configs.RepositoryDetails{
+				ID:            repository.GetID(),
+				Name:          repository.GetName(),
+				Stars:         repository.GetStargazersCount(),
+				DefaultBranch: repository.GetDefaultBranch(),
+				Archived:      repository.GetArchived(),
+			})
 		}
 
 		if response.NextPage == 0 {
@@ -87,6 +112,52 @@ func (c Client) ListRepositories(org string, repoClass string) ([]string, error)
 	return listOfRepositories, nil
 }
 
+// PullRequestEngagement returns the comments and the reactions of a
+// PR, which are only available from its issue. The reviews are left
+// to the caller, who has them from PullRequestReviews.
+func (c Client) PullRequestEngagement(org string, repo string, number int) (configs.Engagement, error) {
+	issue, response, err := c.Client.Issues.Get(c.Context, org, repo, number)
+	if err != nil {
+		return configs.Engagement{}, err
+	}
+	if response.StatusCode != http.StatusOK {
+		return configs.Engagement{}, errors.New("could not get the response for the PR engagement")
+	}
+	return configs.Engagement{
+		Comments:  issue.GetComments(),
+		Reactions: issue.GetReactions().GetTotalCount(),
+	}, nil
+}
+
+// MergedPRsBefore counts the PRs of the author merged before the
+// time, in the repository or in the whole organization when the
+// repository is empty. The merged PRs of the author are searched
+// once for the organization and kept for the other repositories,
+// only the authors with too many of them are searched again.
+func (c Client) MergedPRsBefore(org string, repo string, author string, before time.Time) (int, error) {
+	merged, err := c.authorMergedPRs(org, author)
+	if err != nil {
+		return 0, err
+	}
+	if merged.complete {
+		return merged.count(repo, before), nil
+	}
+	scope := "org:" + org
+	if repo != "" {
+		scope = "repo:" + org + "/" + repo
+	}
+	query := fmt.Sprintf("is:pr is:merged author:%v %v merged:<%v",
+		author, scope, before.UTC().Format(time.RFC3339))
+	result, err :=
+		c.searchIssues(query, &github.SearchOptions{
+			ListOptions: github.ListOptions{PerPage: 1},
+		})
+	if err != nil {
+		return 0, err
+	}
+	return result.GetTotal(), nil
+}
+
 // ListPRs returns the list of PRs for a given organization and repository
 func (c Client) ListPRs(org string, repos []string, daysCount int) ([]configs.PrList, error) {
 	prListOptions := &github.PullRequestListOptions{
@@ -287,6 +358,604 @@ func (c Client) IssueWithLabels(org string, repos []string, issueLabels []string
 	return issueList, nil
 }
 
+// ListActivity returns the PRs updated, their reviews submitted
+// and the issues opened in the last days for the repositories
+func (c Client) ListActivity(org string, repos []string, daysCount int) ([]configs.ActivityList, error) {
+	startDate := time.Now().AddDate(0, 0, daysCount*-1)
+	var activityLists []configs.ActivityList
+
+	for _, repo := range repos {
+		activity := configs.ActivityList{
+			Repository: repo,
+			Reviews:    map[int][]github.PullRequestReview{},
+		}
+		prListOptions := &github.PullRequestListOptions{
+			State:     "all",
+			Sort:      "updated",
+			Direction: "desc",
+			ListOptions: github.ListOptions{
+				PerPage: 100,
+			},
+		}
+		prDateReached := false
+		for !prDateReached {
+			prs, response, err := c.Client.PullRequests.List(c.Context, org, repo, prListOptions)
+			if err != nil {
+				return nil, err
+			}
+			if response.StatusCode != http.StatusOK {
+				return nil, errors.New("could not get the response for the PR activity")
+			}
+			for _, pr := range prs {
+				if pr.GetUpdatedAt().Before(startDate) {
+					prDateReached = true
+					break
+				}
+				reviews, err := c.listReviewsSince(org, repo, pr.GetNumber(), startDate)
+				if err != nil {
+					return nil, err
+				}
+				if len(reviews) != 0 {
+					activity.Reviews[pr.GetNumber()] = reviews
+				}
+				activity.PRs = append(activity.PRs, *pr)
+			}
+			if response.NextPage == 0 {
+				break
+			}
+			prListOptions.Page = response.NextPage
+		}
+
+		issueListOptions := &github.IssueListByRepoOptions{
+			State: "all",
+			Since: startDate,
+			ListOptions: github.ListOptions{
+				PerPage: 100,
+			},
+		}
+		for {
+			issues, response, err := c.Client.Issues.ListByRepo(c.Context, org, repo, issueListOptions)
+			if err != nil {
+				return nil, err
+			}
+			if response.StatusCode != http.StatusOK {
+				return nil, errors.New("could not get the response for the issue activity")
+			}
+			for _, issue := range issues {
+				// the issues list has the PRs too
+				if issue.IsPullRequest() || issue.GetCreatedAt().Before(startDate) {
+					continue
+				}
+				activity.Issues = append(activity.Issues, *issue)
+			}
+			if response.NextPage == 0 {
+				break
+			}
+			issueListOptions.Page = response.NextPage
+		}
+
+		if len(activity.PRs) != 0 || len(activity.Issues) != 0 {
+			activityLists = append(activityLists, activity)
+		}
+	}
+	return activityLists, nil
+}
+
+// PullRequestReviews returns all the reviews of the PR
+func (c Client) PullRequestReviews(org string, repo string, number int) ([]github.PullRequestReview, error) {
+	return c.listReviewsSince(org, repo, number, time.Time{})
+}
+
+// listReviewsSince returns the reviews of the PR submitted after
+// the start date
+func (c Client) listReviewsSince(org string, repo string, number int, startDate time.Time) ([]github.PullRequestReview, error) {
+	listOption := &github.ListOptions{
+		PerPage: 100,
+	}
+	var reviews []github.PullRequestReview
+	for {
+		page, response, err := c.Client.PullRequests.ListReviews(c.Context, org, repo, number, listOption)
+		if err != nil {
+			return nil, err
+		}
+		if response.StatusCode != http.StatusOK {
+			return nil, errors.New("could not get the response for the PR reviews")
+		}
+		for _, review := range page {
+			if review.GetSubmittedAt().Before(startDate) {
+				continue
+			}
+			reviews = append(reviews, *review)
+		}
+		if response.NextPage == 0 {
+			break
+		}
+		listOption.Page = response.NextPage
+	}
+	return reviews, nil
+}
+
+// ListOpenPRs returns all the open PRs of the repositories
+func (c Client) ListOpenPRs(org string, repos []string) ([]configs.PrList, error) {
+	var pullRequests []configs.PrList
+	for _, repo := range repos {
+		prListOptions := &github.PullRequestListOptions{
+			State: "open",
+			ListOptions: github.ListOptions{
+				PerPage: 100,
+			},
+		}
+		var listPullRequests []github.PullRequest
+		for {
+			prs, response, err := c.Client.PullRequests.List(c.Context, org, repo, prListOptions)
+			if err != nil {
+				return nil, err
+			}
+			if response.StatusCode != http.StatusOK {
+				return nil, errors.New("could not get the response for the open PRs")
+			}
+			for _, pr := range prs {
+				listPullRequests = append(listPullRequests, *pr)
+			}
+			if response.NextPage == 0 {
+				break
+			}
+			prListOptions.Page = response.NextPage
+		}
+		if len(listPullRequests) != 0 {
+			pullRequests = append(pullRequests, configs.PrList{
+				Repository: repo,
+				PRs:        listPullRequests,
+			})
+		}
+	}
+	return pullRequests, nil
+}
+
+// ListAssignedIssues returns the open issues of the repositories
+// which are assigned and have at least one of the labels
+func (c Client) ListAssignedIssues(org string, repos []string, issueLabels []string) ([]configs.IssueList, error) {
+	var issueList []configs.IssueList
+	for _, repo := range repos {
+		issueListOptions := &github.IssueListByRepoOptions{
+			State:    "open",
+			Assignee: "*",
+			ListOptions: github.ListOptions{
+				PerPage: 100,
+			},
+		}
+		var listIssues []github.Issue
+		for {
+			issues, response, err := c.Client.Issues.ListByRepo(c.Context, org, repo, issueListOptions)
+			if err != nil {
+				return nil, err
+			}
+			if response.StatusCode != http.StatusOK {
+				return nil, errors.New("could not get the response for the assigned issues")
+			}
+			for _, issue := range issues {
+				if issue.IsPullRequest() || !doesIssueContainLabels(issue, issueLabels) {
+					continue
+				}
+				listIssues = append(listIssues, *issue)
+			}
+			if response.NextPage == 0 {
+				break
+			}
+			issueListOptions.Page = response.NextPage
+		}
+		if len(listIssues) != 0 {
+			issueList = append(issueList, configs.IssueList{
+				Repository: repo,
+				Labels:     issueLabels,
+				Issues:     listIssues,
+			})
+		}
+	}
+	return issueList, nil
+}
+
+// AssigneeLastActivity returns the last time one of the assignees
+// was assigned to or commented on the issue, zero when never
+func (c Client) AssigneeLastActivity(org string, repo string, number int, assignees []string) (time.Time, error) {
+	isAssignee := map[string]bool{}
+	for _, assignee := range assignees {
+		isAssignee[assignee] = true
+	}
+	var lastActivity time.Time
+	active := func(at time.Time) {
+		if at.After(lastActivity) {
+			lastActivity = at
+		}
+	}
+
+	listOption := &github.ListOptions{
+		PerPage: 100,
+	}
+	for {
+		events, response, err := c.Client.Issues.ListIssueEvents(c.Context, org, repo, number, listOption)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if response.StatusCode != http.StatusOK {
+			return time.Time{}, errors.New("could not get the response for the issue events")
+		}
+		for _, event := range events {
+			if event.GetEvent() == "assigned" && isAssignee[event.GetAssignee().GetLogin()] {
+				active(event.GetCreatedAt())
+			}
+		}
+		if response.NextPage == 0 {
+			break
+		}
+		listOption.Page = response.NextPage
+	}
+
+	commentOptions := &github.IssueListCommentsOptions{
+		ListOptions: github.ListOptions{
+			PerPage: 100,
+		},
+	}
+	for {
+		comments, response, err := c.Client.Issues.ListComments(c.Context, org, repo, number, commentOptions)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if response.StatusCode != http.StatusOK {
+			return time.Time{}, errors.New("could not get the response for the issue comments")
+		}
+		for _, comment := range comments {
+			if isAssignee[comment.GetUser().GetLogin()] {
+				active(comment.GetCreatedAt())
+			}
+		}
+		if response.NextPage == 0 {
+			break
+		}
+		commentOptions.Page = response.NextPage
+	}
+	return lastActivity, nil
+}
+
+// RepositoryHealth gathers the health signals of the repository
+// over the last days
+func (c Client) RepositoryHealth(org string, repo configs.RepositoryDetails, daysCount int) (configs.HealthSignals, error) {
+	var signals configs.HealthSignals
+	startDate := time.Now().AddDate(0, 0, daysCount*-1)
+
+	// commits of the default branch
+	commitOptions := &github.CommitsListOptions{
+		SHA:   repo.DefaultBranch,
+		Since: startDate,
+		ListOptions: github.ListOptions{
+			PerPage: 100,
+		},
+	}
+	for {
+		commits, response, err := c.Client.Repositories.ListCommits(c.Context, org, repo.Name, commitOptions)
+		if err != nil {
+			// an empty repository has no commits to list
+			if response != nil && response.StatusCode == http.StatusConflict {
+				break
+			}
+			return configs.HealthSignals{}, err
+		}
+		if response.StatusCode != http.StatusOK {
+			return configs.HealthSignals{}, errors.New("could not get the response for the commits")
+		}
+		signals.Commits += len(commits)
+		if response.NextPage == 0 {
+			break
+		}
+		commitOptions.Page = response.NextPage
+	}
+
+	// merged PRs
+	prListOptions := &github.PullRequestListOptions{
+		State:     "closed",
+		Sort:      "updated",
+		Direction: "desc",
+		ListOptions: github.ListOptions{
+			PerPage: 100,
+		},
+	}
+	prDateReached := false
+	for !prDateReached {
+		prs, response, err := c.Client.PullRequests.List(c.Context, org, repo.Name, prListOptions)
+		if err != nil {
+			return configs.HealthSignals{}, err
+		}
+		if response.StatusCode != http.StatusOK {
+			return configs.HealthSignals{}, errors.New("could not get the response for the pull requests")
+		}
+		for _, pr := range prs {
+			if pr.GetUpdatedAt().Before(startDate) {
+				prDateReached = true
+				break
+			}
+			if pr.MergedAt == nil || pr.GetMergedAt().Before(startDate) {
+				continue
+			}
+			signals.MergedPRs++
+		}
+		if response.NextPage == 0 {
+			break
+		}
+		prListOptions.Page = response.NextPage
+	}
+
+	// the people who merged them, merged_by is only in the response
+	// of a single PR so the merged events of the repository are read,
+	// newest first
+	mergers := map[string]bool{}
+	eventOptions := &github.ListOptions{
+		PerPage: 100,
+	}
+	eventDateReached := false
+	for !eventDateReached && signals.MergedPRs > 0 {
+		events, response, err := c.Client.Issues.ListRepositoryEvents(c.Context, org, repo.Name, eventOptions)
+		if err != nil {
+			return configs.HealthSignals{}, err
+		}
+		if response.StatusCode != http.StatusOK {
+			return configs.HealthSignals{}, errors.New("could not get the response for the issue events")
+		}
+		for _, event := range events {
+			if event.GetCreatedAt().Before(startDate) {
+				eventDateReached = true
+				break
+			}
+			if event.GetEvent() != "merged" {
+				continue
+			}
+			if login := event.GetActor().GetLogin(); login != "" {
+				mergers[login] = true
+			}
+		}
+		if response.NextPage == 0 {
+			break
+		}
+		eventOptions.Page = response.NextPage
+	}
+	signals.Mergers = len(mergers)
+
+	// releases, newest first
+	releaseOptions := &github.ListOptions{
+		PerPage: 100,
+	}
+	releaseDateReached := false
+	for !releaseDateReached {
+		releases, response, err := c.Client.Repositories.ListReleases(c.Context, org, repo.Name, releaseOptions)
+		if err != nil {
+			return configs.HealthSignals{}, err
+		}
+		if response.StatusCode != http.StatusOK {
+			return configs.HealthSignals{}, errors.New("could not get the response for the releases")
+		}
+		for _, release := range releases {
+			if release.PublishedAt == nil {
+				continue
+			}
+			published := release.GetPublishedAt().Time
+			if signals.LastRelease == nil || published.After(*signals.LastRelease) {
+				signals.LastRelease = &published
+			}
+			if published.Before(startDate) {
+				releaseDateReached = true
+				break
+			}
+			signals.Releases++
+		}
+		if response.NextPage == 0 {
+			break
+		}
+		releaseOptions.Page = response.NextPage
+	}
+
+	// issues opened and closed
+	issueListOptions := &github.IssueListByRepoOptions{
+		State: "all",
+		Since: startDate,
+		ListOptions: github.ListOptions{
+			PerPage: 100,
+		},
+	}
+	for {
+		issues, response, err := c.Client.Issues.ListByRepo(c.Context, org, repo.Name, issueListOptions)
+		if err != nil {
+			return configs.HealthSignals{}, err
+		}
+		if response.StatusCode != http.StatusOK {
+			return configs.HealthSignals{}, errors.New("could not get the response for the issues")
+		}
+		for _, issue := range issues {
+			if issue.IsPullRequest() {
+				continue
+			}
+			if !issue.GetCreatedAt().Before(startDate) {
+				signals.IssuesOpened++
+			}
+			if issue.ClosedAt != nil && !issue.GetClosedAt().Before(startDate) {
+				signals.IssuesClosed++
+			}
+		}
+		if response.NextPage == 0 {
+			break
+		}
+		issueListOptions.Page = response.NextPage
+	}
+
+	// community files, the profile has no security policy
+	metrics, _, err := c.Client.Repositories.GetCommunityHealthMetrics(c.Context, org, repo.Name)
+	if err != nil {
+		return configs.HealthSignals{}, err
+	}
+	signals.Readme = metrics.GetFiles().GetReadme() != nil
+	signals.License = metrics.GetFiles().GetLicense() != nil
+	signals.Contributing = metrics.GetFiles().GetContributing() != nil
+	for _, path := range []string{"SECURITY.md", ".github/SECURITY.md", "docs/SECURITY.md"} {
+		_, _, response, err := c.Client.Repositories.GetContents(c.Context, org, repo.Name, path, nil)
+		if err == nil {
+			signals.Security = true
+			break
+		}
+		if response == nil || response.StatusCode != http.StatusNotFound {
+			return configs.HealthSignals{}, err
+		}
+	}
+
+	branch, response, err := c.Client.Repositories.GetBranch(c.Context, org, repo.Name, repo.DefaultBranch)
+	if err != nil {
+		// an empty repository has no default branch yet
+		if response != nil && response.StatusCode == http.StatusNotFound {
+			return signals, nil
+		}
+		return configs.HealthSignals{}, err
+	}
+	signals.BranchProtection = branch.GetProtected()
+	return signals, nil
+}
+
+// ListAllReleases returns the whole release history of the
+// repository, the drafts are left out
+func (c Client) ListAllReleases(org string, repo string) ([]github.RepositoryRelease, error) {
+	listOption := &github.ListOptions{
+		PerPage: 100,
+	}
+	var listReleases []github.RepositoryRelease
+	for {
+		releases, response, err := c.Client.Repositories.ListReleases(c.Context, org, repo, listOption)
+		if err != nil {
+			return nil, err
+		}
+		if response.StatusCode != http.StatusOK {
+			return nil, errors.New("could not get the response for the release history")
+		}
+		for _, release := range releases {
+			if release.GetDraft() {
+				continue
+			}
+			listReleases = append(listReleases, *release)
+		}
+		if response.NextPage == 0 {
+			break
+		}
+		listOption.Page = response.NextPage
+	}
+	return listReleases, nil
+}
+
+// ListCommits returns the commits of the default branch of the
+// repository during the days, the newest first. The files of every
+// commit are fetched one commit at a time when asked for.
+func (c Client) ListCommits(
+	org string,
+	repo configs.RepositoryDetails,
+	daysCount int,
+	withFiles bool,
+) ([]github.RepositoryCommit, error) {
+	commitOptions := &github.CommitsListOptions{
+		SHA:   repo.DefaultBranch,
+		Since: time.Now().AddDate(0, 0, daysCount*-1),
+		ListOptions: github.ListOptions{
+			PerPage: 100,
+		},
+	}
+	var listCommits []github.RepositoryCommit
+	for {
+		commits, response, err := c.Client.Repositories.ListCommits(c.Context, org, repo.Name, commitOptions)
+		if err != nil {
+			// an empty repository has no commits to list
+			if response != nil && response.StatusCode == http.StatusConflict {
+				return nil, nil
+			}
+			return nil, err
+		}
+		if response.StatusCode != http.StatusOK {
+			return nil, errors.New("could not get the response for the commits")
+		}
+		for _, commit := range commits {
+			if withFiles {
+				var commitResponse *github.Response
+				commit, commitResponse, err = c.Client.Repositories.GetCommit(c.Context, org, repo.Name, commit.GetSHA())
+				if err != nil {
+					return nil, err
+				}
+				if commitResponse.StatusCode != http.StatusOK {
+					return nil, errors.New("could not get the response for the commit")
+				}
+			}
+			listCommits = append(listCommits, *commit)
+		}
+		if response.NextPage == 0 {
+			break
+		}
+		commitOptions.Page = response.NextPage
+	}
+	return listCommits, nil
+}
+
+// ListMilestones returns the open milestones of the repository
+func (c Client) ListMilestones(org string, repo string) ([]github.Milestone, error) {
+	milestoneOptions := &github.MilestoneListOptions{
+		State:     "open",
+		Sort:      "due_on",
+		Direction: "asc",
+		ListOptions: github.ListOptions{
+			PerPage: 100,
+		},
+	}
+	var listMilestones []github.Milestone
+	for {
+		milestones, response, err := c.Client.Issues.ListMilestones(c.Context, org, repo, milestoneOptions)
+		if err != nil {
+			return nil, err
+		}
+		if response.StatusCode != http.StatusOK {
+			return nil, errors.New("could not get the response for the milestones")
+		}
+		for _, milestone := range milestones {
+			listMilestones = append(listMilestones, *milestone)
+		}
+		if response.NextPage == 0 {
+			break
+		}
+		milestoneOptions.Page = response.NextPage
+	}
+	return listMilestones, nil
+}
+
+// MilestoneClosedSince counts the issues and the PRs of the
+// milestone which were closed since the date
+func (c Client) MilestoneClosedSince(org string, repo string, number int, since time.Time) (int, error) {
+	issueListOptions := &github.IssueListByRepoOptions{
+		Milestone: strconv.Itoa(number),
+		State:     "closed",
+		Since:     since,
+		ListOptions: github.ListOptions{
+			PerPage: 100,
+		},
+	}
+	closed := 0
+	for {
+		issues, response, err := c.Client.Issues.ListByRepo(c.Context, org, repo, issueListOptions)
+		if err != nil {
+			return 0, err
+		}
+		for _, issue := range issues {
+			// since is on the update, the issue may be closed before
+			if !issue.GetClosedAt().Before(since) {
+				closed++
+			}
+		}
+		if response.NextPage == 0 {
+			break
+		}
+		issueListOptions.Page = response.NextPage
+	}
+	return closed, nil
+}
+
 /**
 Utility function to check if the issue contains at least one of the desired labels
 */
@@ -0,0 +1,142 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v33/github"
+)
+
+// maxSearchRetries is the number of times a rate limited search is
+// retried, the search API allows 30 requests a minute
+const maxSearchRetries = 3
+
+// mergedSearchPageSize is the number of merged PRs of an author
+// read at once, the authors with more have their count searched
+const mergedSearchPageSize = 100
+
+// searchIssues runs the search, waiting for the rate limit to reset
+// when it is reached
+func (c Client) searchIssues(query string, options *github.SearchOptions) (*github.IssuesSearchResult, error) {
+	for attempt := 0; ; attempt++ {
+		result, response, err := c.Client.Search.Issues(c.Context, query, options)
+		var wait time.Duration
+		var rateLimitError *github.RateLimitError
+		var abuseError *github.AbuseRateLimitError
+		switch {
+		case errors.As(err, &rateLimitError):
+			wait = time.Until(rateLimitError.Rate.Reset.Time) + time.Second
+		case errors.As(err, &abuseError):
+			wait = abuseError.GetRetryAfter()
+			if wait <= 0 {
+				wait = time.Minute
+			}
+		case err != nil:
+			return nil, err
+		case response.StatusCode != http.StatusOK:
+			return nil, errors.New("could not get the response for the search")
+		default:
+			return result, nil
+		}
+		if attempt == maxSearchRetries {
+			return nil, err
+		}
+		log.Printf("Search rate limit reached, retrying in %v", wait.Round(time.Second))
+		time.Sleep(wait)
+	}
+}
+
+// mergedPRs has the PRs an author merged in an organization, read
+// once for all the repositories
+type mergedPRs struct {
+	// closedAt of the merged PRs by repository name, a merged PR is
+	// closed when it is merged
+	closedAt map[string][]time.Time
+	// complete is false when the author has more merged PRs than
+	// were read
+	complete bool
+}
+
+// mergedPRCache keeps the merged PRs by organization and author for
+// the whole run
+type mergedPRCache struct {
+	mutex  sync.Mutex
+	byUser map[string]*mergedPRs
+}
+
+func newMergedPRCache() *mergedPRCache {
+	return &mergedPRCache{byUser: map[string]*mergedPRs{}}
+}
+
+// authorMergedPRs reads the merged PRs of the author in the
+// organization, from the cache when they were read before
+func (c Client) authorMergedPRs(org string, author string) (*mergedPRs, error) {
+	key := strings.ToLower(org + "/" + author)
+	if c.mergedPRs != nil {
+		c.mergedPRs.mutex.Lock()
+		defer c.mergedPRs.mutex.Unlock()
+		if merged, found := c.mergedPRs.byUser[key]; found {
+			return merged, nil
+		}
+	}
+	query := fmt.Sprintf("is:pr is:merged author:%v org:%v", author, org)
+	result, err := c.searchIssues(query, &github.SearchOptions{
+		Sort:        "created",
+		Order:       "asc",
+		ListOptions: github.ListOptions{PerPage: mergedSearchPageSize},
+	})
+	if err != nil {
+		return nil, err
+	}
+	merged := &mergedPRs{
+		closedAt: map[string][]time.Time{},
+		complete: result.GetTotal() <= len(result.Issues),
+	}
+	for _, issue := range result.Issues {
+		// https://api.github.com/repos/<org>/<repository>
+		repository := issue.GetRepositoryURL()[strings.LastIndex(issue.GetRepositoryURL(), "/")+1:]
+		merged.closedAt[repository] = append(merged.closedAt[repository], issue.GetClosedAt())
+	}
+	if c.mergedPRs != nil {
+		c.mergedPRs.byUser[key] = merged
+	}
+	return merged, nil
+}
+
+// count is the number of PRs merged before the time, in the
+// repository or in all of them when it is empty
+func (m *mergedPRs) count(repo string, before time.Time) int {
+	count := 0
+	for repository, closedAt := range m.closedAt {
+		if repo != "" && !strings.EqualFold(repository, repo) {
+			continue
+		}
+		for _, closed := range closedAt {
+			if closed.Before(before) {
+				count++
+			}
+		}
+	}
+	return count
+}
@@ -0,0 +1,153 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package commits summarizes the commits pushed to the default
+// branch of the repositories, which the PRs miss for the projects
+// pushing straight to the branch or using merge queues
+package commits
+
+import (
+	"github-updates/internal/pkg/configs"
+	"github-updates/internal/pkg/contributors"
+	"regexp"
+	"strings"
+
+	"github.com/google/go-github/v33/github"
+)
+
+// coAuthorTrailer matches the "Co-authored-by: Name <email>" lines
+// of a commit message
+var coAuthorTrailer = regexp.MustCompile(`(?im)^co-authored-by:\s*(.*?)\s*<([^>]*)>\s*$`)
+
+// noReplyDomain is the domain of the private emails of GitHub, the
+// local part is "<id>+<login>" or the login for the older accounts
+const noReplyDomain = "@users.noreply.github.com"
+
+// Summarize lists the commits of the repository with their totals,
+// the commits of the bots are left out when asked for
+func Summarize(
+	repo configs.RepositoryDetails,
+	repositoryCommits []github.RepositoryCommit,
+	excludeBots bool,
+) configs.CommitList {
+	commitList := configs.CommitList{
+		Repository: repo.Name,
+		Branch:     repo.DefaultBranch,
+	}
+	authors := map[string]bool{}
+	files := map[string]bool{}
+	for _, repositoryCommit := range repositoryCommits {
+		author := commitAuthor(repositoryCommit)
+		if excludeBots && (contributors.IsBot(repositoryCommit.Author) || strings.HasSuffix(author.Name, "[bot]")) {
+			continue
+		}
+		commit := configs.Commit{
+			SHA:          repositoryCommit.GetSHA(),
+			URL:          repositoryCommit.GetHTMLURL(),
+			Subject:      Subject(repositoryCommit.GetCommit().GetMessage()),
+			Author:       author,
+			CoAuthors:    CoAuthors(repositoryCommit.GetCommit().GetMessage()),
+			Date:         repositoryCommit.GetCommit().GetCommitter().GetDate(),
+			FilesChanged: len(repositoryCommit.Files),
+			Additions:    repositoryCommit.GetStats().GetAdditions(),
+			Deletions:    repositoryCommit.GetStats().GetDeletions(),
+		}
+		authors[authorKey(commit.Author)] = true
+		for _, coAuthor := range commit.CoAuthors {
+			authors[authorKey(coAuthor)] = true
+		}
+		for _, file := range repositoryCommit.Files {
+			files[file.GetFilename()] = true
+		}
+		commitList.Additions += commit.Additions
+		commitList.Deletions += commit.Deletions
+		commitList.Commits = append(commitList.Commits, commit)
+	}
+	commitList.Authors = len(authors)
+	commitList.FilesChanged = len(files)
+	return commitList
+}
+
+// Subject is the first line of the commit message
+func Subject(message string) string {
+	return strings.TrimSpace(strings.SplitN(message, "\n", 2)[0])
+}
+
+// CoAuthors reads the co-authors from the trailers of the commit
+// message, the login is known for the private emails of GitHub
+func CoAuthors(message string) []configs.CommitAuthor {
+	var coAuthors []configs.CommitAuthor
+	seen := map[string]bool{}
+	for _, match := range coAuthorTrailer.FindAllStringSubmatch(message, -1) {
+		coAuthor := configs.CommitAuthor{
+			Name:  match[1],
+			Email: match[2],
+			Login: noReplyLogin(match[2]),
+		}
+		if coAuthor.Login != "" {
+			coAuthor.ProfileURL = "https://github.com/" + coAuthor.Login
+		}
+		if key := authorKey(coAuthor); !seen[key] {
+			seen[key] = true
+			coAuthors = append(coAuthors, coAuthor)
+		}
+	}
+	return coAuthors
+}
+
+// commitAuthor is the git author of the commit, with the GitHub
+// account when the email is linked to one
+func commitAuthor(repositoryCommit github.RepositoryCommit) configs.CommitAuthor {
+	gitAuthor := repositoryCommit.GetCommit().GetAuthor()
+	author := configs.CommitAuthor{
+		Name:       gitAuthor.GetName(),
+		Email:      gitAuthor.GetEmail(),
+		Login:      repositoryCommit.GetAuthor().GetLogin(),
+		ProfileURL: repositoryCommit.GetAuthor().GetHTMLURL(),
+		AvatarURL:  repositoryCommit.GetAuthor().GetAvatarURL(),
+	}
+	if author.Login == "" {
+		author.Login = noReplyLogin(author.Email)
+		if author.Login != "" {
+			author.ProfileURL = "https://github.com/" + author.Login
+		}
+	}
+	return author
+}
+
+// noReplyLogin is the login of a private email of GitHub
+func noReplyLogin(email string) string {
+	email = strings.ToLower(email)
+	if !strings.HasSuffix(email, noReplyDomain) {
+		return ""
+	}
+	local := strings.TrimSuffix(email, noReplyDomain)
+	if index := strings.Index(local, "+"); index >= 0 {
+		local = local[index+1:]
+	}
+	return local
+}
+
+// authorKey identifies an author by the login, else by the email
+func authorKey(author configs.CommitAuthor) string {
+	switch {
+	case author.Login != "":
+		return "login:" + strings.ToLower(author.Login)
+	case author.Email != "":
+		return "email:" + strings.ToLower(author.Email)
+	}
+	return "name:" + author.Name
+}
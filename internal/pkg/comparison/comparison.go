@@ -0,0 +1,137 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package comparison tells how the reports changed since the
+// previous run
+package comparison
+
+import (
+	"github-updates/internal/pkg/configs"
+	"github-updates/internal/pkg/schema"
+	"math"
+	"sort"
+)
+
+// Compare counts the items of every organization and repository in
+// both documents, the result is keyed by the organization of the
+// current document. The repositories are new or removed when they
+// are only in one of the repository lists, which needs both documents
+// to carry the lists, else only the counts are compared. Only the two
+// documents are compared, the history store is not read.
+func Compare(previous schema.Document, current schema.Document) map[string]configs.Delta {
+	previousOrgs := map[string]schema.Organization{}
+	for _, org := range previous.Organizations {
+		previousOrgs[org.Name] = org
+	}
+
+	deltas := map[string]configs.Delta{}
+	for _, org := range current.Organizations {
+		previousOrg, found := previousOrgs[org.Name]
+		delta := configs.Delta{
+			Since: previous.GeneratedAt,
+			New:   !found,
+		}
+		before := countItems(previousOrg)
+		now := countItems(org)
+		listedBefore := listed(previousOrg)
+		listedNow := listed(org)
+		// the repositories with items are not the whole organization,
+		// so without both lists nothing is known to be added or removed
+		tracked := len(previousOrg.ListedRepositories) > 0 && len(org.ListedRepositories) > 0
+		for _, repo := range listedNow {
+			count := now[repo]
+			previousCount := before[repo]
+			added := tracked && !contains(listedBefore, repo)
+			if !added && count == 0 && previousCount == 0 {
+				continue
+			}
+			delta.Repositories = append(delta.Repositories, configs.RepositoryDelta{
+				Repository: repo,
+				Current:    count,
+				Previous:   previousCount,
+				Change:     count - previousCount,
+				Percent:    percent(count, previousCount),
+				New:        added,
+			})
+		}
+		var missing []string
+		for _, repo := range listedBefore {
+			if !contains(listedNow, repo) && (tracked || before[repo] > 0) {
+				missing = append(missing, repo)
+			}
+		}
+		sort.Strings(missing)
+		for _, repo := range missing {
+			delta.Repositories = append(delta.Repositories, configs.RepositoryDelta{
+				Repository: repo,
+				Previous:   before[repo],
+				Change:     -before[repo],
+				Percent:    percent(0, before[repo]),
+				Removed:    tracked,
+			})
+		}
+		for _, count := range now {
+			delta.Current += count
+		}
+		for _, count := range before {
+			delta.Previous += count
+		}
+		delta.Change = delta.Current - delta.Previous
+		delta.Percent = percent(delta.Current, delta.Previous)
+		deltas[org.Name] = delta
+	}
+	return deltas
+}
+
+func countItems(org schema.Organization) map[string]int {
+	counts := map[string]int{}
+	for _, repo := range org.Repositories {
+		counts[repo.Name] = len(repo.Items)
+	}
+	return counts
+}
+
+// listed returns the repository list of the organization, or the
+// repositories of the document for the ones written without a list
+func listed(org schema.Organization) []string {
+	if len(org.ListedRepositories) > 0 {
+		return org.ListedRepositories
+	}
+	var names []string
+	for _, repo := range org.Repositories {
+		names = append(names, repo.Name)
+	}
+	return names
+}
+
+func contains(names []string, name string) bool {
+	for _, candidate := range names {
+		if candidate == name {
+			return true
+		}
+	}
+	return false
+}
+
+// percent of the change rounded to a tenth, not set when there was
+// nothing to compare with
+func percent(current int, previous int) *float64 {
+	if previous == 0 {
+		return nil
+	}
+	value := math.Round(float64(current-previous)/float64(previous)*1000) / 10
+	return &value
+}
@@ -0,0 +1,91 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package comparison
+
+import (
+	"reflect"
+	"testing"
+
+	"github-updates/internal/pkg/schema"
+)
+
+func document(listed []string, items map[string]int) schema.Document {
+	org := schema.Organization{Name: "org", ListedRepositories: listed}
+	for _, name := range listed {
+		if items[name] > 0 {
+			org.Repositories = append(org.Repositories, schema.Repository{
+				Name:  name,
+				Items: make([]schema.Item, items[name]),
+			})
+		}
+	}
+	return schema.Document{Organizations: []schema.Organization{org}}
+}
+
+func TestCompareRepositoryLists(t *testing.T) {
+	previous := document([]string{"busy", "quiet", "gone"}, map[string]int{"busy": 2, "gone": 1})
+	current := document([]string{"busy", "quiet", "added"}, map[string]int{"busy": 3})
+	delta := Compare(previous, current)["org"]
+
+	var added, removed, compared []string
+	for _, repo := range delta.Repositories {
+		switch {
+		case repo.New:
+			added = append(added, repo.Repository)
+		case repo.Removed:
+			removed = append(removed, repo.Repository)
+		default:
+			compared = append(compared, repo.Repository)
+		}
+	}
+	if !reflect.DeepEqual(added, []string{"added"}) {
+		t.Errorf("new repositories are %v, want [added]", added)
+	}
+	if !reflect.DeepEqual(removed, []string{"gone"}) {
+		t.Errorf("removed repositories are %v, want [gone]", removed)
+	}
+	if !reflect.DeepEqual(compared, []string{"busy"}) {
+		t.Errorf("compared repositories are %v, want [busy]", compared)
+	}
+	if delta.Current != 3 || delta.Previous != 3 || delta.Change != 0 {
+		t.Errorf("counts are %v and %v, want 3 and 3", delta.Current, delta.Previous)
+	}
+}
+
+func TestCompareWithoutList(t *testing.T) {
+	// dormant had no items in the previous run, written without a list
+	previous := document([]string{"busy", "quiet", "dormant", "moved"},
+		map[string]int{"busy": 2, "quiet": 1, "moved": 4})
+	previous.Organizations[0].ListedRepositories = nil
+	current := document([]string{"busy", "quiet", "dormant"}, map[string]int{"busy": 2, "dormant": 1})
+	delta := Compare(previous, current)["org"]
+
+	var compared []string
+	for _, repo := range delta.Repositories {
+		if repo.New || repo.Removed {
+			t.Errorf("%v is new or removed, the previous run has no list", repo.Repository)
+		}
+		compared = append(compared, repo.Repository)
+	}
+	want := []string{"busy", "quiet", "dormant", "moved"}
+	if !reflect.DeepEqual(compared, want) {
+		t.Errorf("compared repositories are %v, want %v", compared, want)
+	}
+	if delta.Current != 3 || delta.Previous != 7 {
+		t.Errorf("counts are %v and %v, want 3 and 7", delta.Current, delta.Previous)
+	}
+}
@@ -17,6 +17,7 @@
 package configs
 
 import (
+	"fmt"
 	"github-updates/internal/pkg/utils"
 	"io/ioutil"
 	"log"
@@ -30,6 +31,107 @@ type Configuration struct {
 	Issues              IssueConfiguration       `yaml:"issues"`
 	PullRequests        PullRequestConfiguration `yaml:"pull-requests"`
 	Releases            ReleaseConfiguration     `yaml:"releases"`
+	Contributors        ContributorConfiguration `yaml:"contributors"`
+	Stale               StaleConfiguration       `yaml:"stale"`
+	Scorecard           ScorecardConfiguration   `yaml:"scorecard"`
+	Commits             CommitConfiguration      `yaml:"commits"`
+	Discussions         DiscussionConfiguration  `yaml:"discussions"`
+	Security            SecurityConfiguration    `yaml:"security"`
+	Milestones          MilestoneConfiguration   `yaml:"milestones"`
+	Email               EmailConfiguration       `yaml:"email"`
+	Publishers          PublisherConfiguration   `yaml:"publishers"`
+	Site                SiteConfiguration        `yaml:"site"`
+}
+
+// SiteConfiguration writes the history of the newsletter as a
+// static site with an issue folder per week
+type SiteConfiguration struct {
+	Enabled bool   `yaml:"enabled"`
+	Root    string `yaml:"root"`
+	Title   string `yaml:"title"`
+	BaseURL string `yaml:"base-url"`
+}
+
+// PublisherConfiguration posts the digest of the run to the
+// chat channels
+type PublisherConfiguration struct {
+	Title    string                 `yaml:"title"`
+	Digest   DigestConfiguration    `yaml:"digest"`
+	Slack    WebhookPublisher       `yaml:"slack"`
+	Discord  WebhookPublisher       `yaml:"discord"`
+	Webhooks []WebhookConfiguration `yaml:"webhooks"`
+	Mastodon MastodonConfiguration  `yaml:"mastodon"`
+}
+
+// MastodonConfiguration announces the new releases on a Mastodon
+// account, the access token is read from the environment
+type MastodonConfiguration struct {
+	Enabled       bool     `yaml:"enabled"`
+	Instance      string   `yaml:"instance"`
+	Mode          string   `yaml:"mode"`
+	ThreadTitle   string   `yaml:"thread-title"`
+	Visibility    string   `yaml:"visibility"`
+	Hashtags      []string `yaml:"hashtags"`
+	MaxCharacters int      `yaml:"max-characters"`
+	StateFile     string   `yaml:"state-file"`
+}
+
+// WebhookConfiguration is a generic webhook, the body is rendered
+// from the template or the preset
+type WebhookConfiguration struct {
+	Name     string            `yaml:"name"`
+	Enabled  bool              `yaml:"enabled"`
+	Preset   string            `yaml:"preset"`
+	URL      string            `yaml:"url"`
+	Method   string            `yaml:"method"`
+	Template string            `yaml:"template"`
+	Headers  map[string]string `yaml:"headers"`
+	Auth     WebhookAuth       `yaml:"auth"`
+	Matrix   MatrixRoom        `yaml:"matrix"`
+}
+
+// WebhookAuth is the authorization of the webhook, the secret is
+// read from the environment variable SecretEnv
+type WebhookAuth struct {
+	Type      string `yaml:"type"`
+	SecretEnv string `yaml:"secret-env"`
+	Username  string `yaml:"username"`
+	Header    string `yaml:"header"`
+}
+
+// MatrixRoom is the room the matrix preset posts to
+type MatrixRoom struct {
+	Homeserver string `yaml:"homeserver"`
+	RoomID     string `yaml:"room-id"`
+}
+
+// DigestConfiguration limits the number of items in the digest
+type DigestConfiguration struct {
+	TopReleases int `yaml:"top-releases"`
+	TopProjects int `yaml:"top-projects"`
+	TopIssues   int `yaml:"top-issues"`
+}
+
+// WebhookPublisher is an incoming webhook, the url can be set
+// in the environment instead as it is a secret
+type WebhookPublisher struct {
+	Enabled    bool   `yaml:"enabled"`
+	WebhookURL string `yaml:"webhook-url"`
+}
+
+// EmailConfiguration is the delivery of the newsletter over SMTP,
+// the password is read from the environment
+type EmailConfiguration struct {
+	Enabled     bool     `yaml:"enabled"`
+	Host        string   `yaml:"host"`
+	Port        int      `yaml:"port"`
+	Username    string   `yaml:"username"`
+	StartTLS    bool     `yaml:"starttls"`
+	From        string   `yaml:"from"`
+	To          []string `yaml:"to"`
+	Subject     string   `yaml:"subject"`
+	Reports     []string `yaml:"reports"`
+	Stylesheets []string `yaml:"stylesheets"`
 }
 
 type IssueConfiguration struct {
@@ -39,6 +141,21 @@ type IssueConfiguration struct {
 	IssueReportShouldRun    bool                    `yaml:"should-run"`
 	IssueDataFile           string                  `yaml:"data-file"`
 	IssueExternalTemplate   ElementExternalTemplate `yaml:"external-template"`
+	IssueOutputs            []ReportOutput          `yaml:"outputs"`
+}
+
+// DiscussionConfiguration is the report of the GitHub Discussions,
+// the new discussions and the answered questions by category. The
+// categories are matched by name, all of them when it is empty.
+// Discussions are only in the GraphQL API, which needs a token.
+type DiscussionConfiguration struct {
+	DiscussionCategories         []string                `yaml:"categories"`
+	DiscussionCreatedHistoryDays int                     `yaml:"created-history-days"`
+	DiscussionSummaryFileName    string                  `yaml:"summary-filename"`
+	DiscussionReportShouldRun    bool                    `yaml:"should-run"`
+	DiscussionDataFile           string                  `yaml:"data-file"`
+	DiscussionExternalTemplate   ElementExternalTemplate `yaml:"external-template"`
+	DiscussionOutputs            []ReportOutput          `yaml:"outputs"`
 }
 
 type PullRequestConfiguration struct {
@@ -46,6 +163,135 @@ type PullRequestConfiguration struct {
 	PRReportShouldRun  bool                    `yaml:"should-run"`
 	PRDataFile         string                  `yaml:"data-file"`
 	PRExternalTemplate ElementExternalTemplate `yaml:"external-template"`
+	PROutputs          []ReportOutput          `yaml:"outputs"`
+	PRNewContributors  NewContributors         `yaml:"new-contributors"`
+	PRCycleTime        CycleTimeConfiguration  `yaml:"cycle-time"`
+}
+
+// CycleTimeConfiguration adds the review latency and the time to
+// merge to the PRs, the per repository statistics are written as a
+// report of their own
+type CycleTimeConfiguration struct {
+	Enabled         bool           `yaml:"enabled"`
+	SummaryFileName string         `yaml:"summary-filename"`
+	DataFile        string         `yaml:"data-file"`
+	Outputs         []ReportOutput `yaml:"outputs"`
+}
+
+// NewContributors finds the authors of the PRs who never had a PR
+// merged before, in the repository or in the organization by Scope
+type NewContributors struct {
+	Enabled bool   `yaml:"enabled"`
+	Scope   string `yaml:"scope"`
+}
+
+// ContributorConfiguration is the report of the activity of every
+// author over the last Days, scrape-duration-days when it is 0
+type ContributorConfiguration struct {
+	ContributorSummaryFileName string         `yaml:"summary-filename"`
+	ContributorReportShouldRun bool           `yaml:"should-run"`
+	ContributorDataFile        string         `yaml:"data-file"`
+	ContributorOutputs         []ReportOutput `yaml:"outputs"`
+	ContributorDays            int            `yaml:"days"`
+	ExcludeBots                bool           `yaml:"exclude-bots"`
+	ExcludeUsers               []string       `yaml:"exclude-users"`
+}
+
+// CommitConfiguration is the report of the commits pushed to the
+// default branch of every repository. The files of each commit are
+// only in the response of the single commit, set FilesChanged to
+// fetch them.
+type CommitConfiguration struct {
+	CommitSummaryFileName string         `yaml:"summary-filename"`
+	CommitReportShouldRun bool           `yaml:"should-run"`
+	CommitDataFile        string         `yaml:"data-file"`
+	CommitOutputs         []ReportOutput `yaml:"outputs"`
+	CommitDays            int            `yaml:"days"`
+	FilesChanged          bool           `yaml:"files-changed"`
+	ExcludeBots           bool           `yaml:"exclude-bots"`
+}
+
+// SecurityConfiguration is the digest of the published security
+// advisories of the repositories and of the releases and the PRs
+// which fix a vulnerability. A PR is a fix when it has one of the
+// Labels, a PR or a release when its title has one of the Keywords
+// or it refers to a CVE or a GHSA.
+type SecurityConfiguration struct {
+	SecuritySummaryFileName string         `yaml:"summary-filename"`
+	SecurityReportShouldRun bool           `yaml:"should-run"`
+	SecurityDataFile        string         `yaml:"data-file"`
+	SecurityOutputs         []ReportOutput `yaml:"outputs"`
+	SecurityDays            int            `yaml:"days"`
+	Labels                  []string       `yaml:"labels"`
+	Keywords                []string       `yaml:"keywords"`
+}
+
+// MilestoneConfiguration is the progress report of the open
+// milestones. Only the milestones due within DueWithinDays are
+// listed, all of them when it is 0, the ones with no due date last.
+type MilestoneConfiguration struct {
+	MilestoneSummaryFileName string         `yaml:"summary-filename"`
+	MilestoneReportShouldRun bool           `yaml:"should-run"`
+	MilestoneDataFile        string         `yaml:"data-file"`
+	MilestoneOutputs         []ReportOutput `yaml:"outputs"`
+	MilestoneDays            int            `yaml:"days"`
+	DueWithinDays            int            `yaml:"due-within-days"`
+}
+
+// StaleConfiguration is the report of the open PRs and issues which
+// are stuck, Thresholds apply to the organizations which do not set
+// their own
+type StaleConfiguration struct {
+	StaleSummaryFileName string          `yaml:"summary-filename"`
+	StaleReportShouldRun bool            `yaml:"should-run"`
+	StaleDataFile        string          `yaml:"data-file"`
+	StaleOutputs         []ReportOutput  `yaml:"outputs"`
+	Thresholds           StaleThresholds `yaml:"thresholds"`
+}
+
+// StaleThresholds are the days after which an open PR with no
+// activity, a PR with no reviewer and an issue whose assignee is
+// silent are reported. IssueLabels select the issues, the issue
+// tags are used when they are empty.
+type StaleThresholds struct {
+	InactiveDays       int      `yaml:"inactive-days" json:"inactiveDays"`
+	ReviewWaitDays     int      `yaml:"review-wait-days" json:"reviewWaitDays"`
+	AssigneeSilentDays int      `yaml:"assignee-silent-days" json:"assigneeSilentDays"`
+	IssueLabels        []string `yaml:"issue-labels" json:"issueLabels,omitempty"`
+}
+
+// ScorecardConfiguration is the health report of every repository
+// over the last Days, 90 when it is 0. The weights and thresholds
+// not set fall back to the defaults.
+type ScorecardConfiguration struct {
+	ScorecardSummaryFileName string              `yaml:"summary-filename"`
+	ScorecardReportShouldRun bool                `yaml:"should-run"`
+	ScorecardDataFile        string              `yaml:"data-file"`
+	ScorecardOutputs         []ReportOutput      `yaml:"outputs"`
+	Days                     int                 `yaml:"days"`
+	Weights                  ScorecardWeights    `yaml:"weights"`
+	Thresholds               ScorecardThresholds `yaml:"thresholds"`
+}
+
+// ScorecardWeights are the weights of the health signals
+type ScorecardWeights struct {
+	Activity         float64 `yaml:"activity" json:"activity"`
+	ReleaseCadence   float64 `yaml:"release-cadence" json:"releaseCadence"`
+	IssueGrowth      float64 `yaml:"issue-growth" json:"issueGrowth"`
+	BusFactor        float64 `yaml:"bus-factor" json:"busFactor"`
+	CommunityFiles   float64 `yaml:"community-files" json:"communityFiles"`
+	BranchProtection float64 `yaml:"branch-protection" json:"branchProtection"`
+}
+
+// ScorecardThresholds are the values a healthy repository reaches,
+// the commits and merged PRs of Activity, a release in ReleaseDays,
+// no more than MaxIssueGrowth issues opened than closed and
+// BusFactor people merging
+type ScorecardThresholds struct {
+	Activity       int `yaml:"activity" json:"activity"`
+	ReleaseDays    int `yaml:"release-days" json:"releaseDays"`
+	MaxIssueGrowth int `yaml:"max-issue-growth" json:"maxIssueGrowth"`
+	BusFactor      int `yaml:"bus-factor" json:"busFactor"`
 }
 
 type ReleaseConfiguration struct {
@@ -53,20 +299,100 @@ type ReleaseConfiguration struct {
 	ReleaseReportShouldRun  bool                    `yaml:"should-run"`
 	ReleaseDataFile         string                  `yaml:"data-file"`
 	ReleaseExternalTemplate ElementExternalTemplate `yaml:"external-template"`
+	ReleaseOutputs          []ReportOutput          `yaml:"outputs"`
+	ReleaseTimeline         TimelineConfiguration   `yaml:"timeline"`
+}
+
+// TimelineConfiguration is the release cadence over the whole
+// release history of the repositories, the ones which did not
+// release for SLADays are flagged
+type TimelineConfiguration struct {
+	Enabled            bool           `yaml:"enabled"`
+	SummaryFileName    string         `yaml:"summary-filename"`
+	DataFile           string         `yaml:"data-file"`
+	Outputs            []ReportOutput `yaml:"outputs"`
+	SLADays            int            `yaml:"sla-days"`
+	IncludePrereleases bool           `yaml:"include-prereleases"`
+}
+
+// ReportOutput is one additional rendering of a report summary.
+// Template is optional, the built-in template for the format
+// is used when it is left empty. Title and Link describe the
+// feed for the atom and rss formats. Columns select and order
+// the columns of the csv and tsv exports.
+type ReportOutput struct {
+	Format   string   `yaml:"format"`
+	Path     string   `yaml:"path"`
+	Template string   `yaml:"template"`
+	Title    string   `yaml:"title"`
+	Link     string   `yaml:"link"`
+	Columns  []string `yaml:"columns"`
 }
 
 type ElementExternalTemplate struct {
-	Input     string `yaml:"input"`
-	Output    string `yaml:"output"`
-	Summary   string `yaml:"summary"`
-	Generated string `yaml:"sum-generated"`
+	Input             string   `yaml:"input"`
+	Output            string   `yaml:"output"`
+	Summary           string   `yaml:"summary"`
+	Generated         string   `yaml:"sum-generated"`
+	Feeds             []string `yaml:"feeds"`
+	FrontMatter       string   `yaml:"front-matter"`
+	ContentConvention string   `yaml:"content-convention"`
+	Trending          Trending `yaml:"trending"`
+}
+
+// Trending selects the items of the summary file, Count items
+// sorted by SortBy with at most PerRepoCap from any repository
+type Trending struct {
+	Count      int            `yaml:"count"`
+	SortBy     string         `yaml:"sort-by"`
+	PerRepoCap int            `yaml:"per-repo-cap"`
+	Weights    RankingWeights `yaml:"weights"`
+}
+
+// RankingWeights are the weights of the signals in the score of an
+// item, the defaults are used when all of them are zero
+type RankingWeights struct {
+	Reactions     float64 `yaml:"reactions"`
+	Comments      float64 `yaml:"comments"`
+	Reviews       float64 `yaml:"reviews"`
+	Stars         float64 `yaml:"stars"`
+	AuthorNovelty float64 `yaml:"author-novelty"`
 }
 
 type GlobalConfiguration struct {
-	Organizations    []Organization   `yaml:"organizations"`
-	DaysCount        int              `yaml:"scrape-duration-days"`
-	ExternalTemplate ExternalTemplate `yaml:"external-template"`
-	RepoClass        string           `yaml:"scrape-repo-class"`
+	Organizations    []Organization      `yaml:"organizations"`
+	DaysCount        int                 `yaml:"scrape-duration-days"`
+	ExternalTemplate ExternalTemplate    `yaml:"external-template"`
+	RepoClass        string              `yaml:"scrape-repo-class"`
+	Storage          Storage             `yaml:"storage"`
+	Comparison       Comparison          `yaml:"comparison"`
+	Charts           ChartsConfiguration `yaml:"charts"`
+}
+
+// ChartsConfiguration draws the SVG charts of the pull request and
+// release reports, written next to their summaries. Weeks is the
+// length of the weekly activity sparkline when the SQLite history
+// is kept.
+type ChartsConfiguration struct {
+	Enabled bool `yaml:"enabled"`
+	Weeks   int  `yaml:"weeks"`
+}
+
+// Comparison compares the pull request, release and issue reports
+// with the data files of the previous run before they are replaced
+type Comparison struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// Storage lists the sinks keeping the history of the runs
+type Storage struct {
+	SQLite SQLiteStorage `yaml:"sqlite"`
+}
+
+// SQLiteStorage is the SQLite database of the collected activity
+type SQLiteStorage struct {
+	Enabled bool   `yaml:"enabled"`
+	Path    string `yaml:"path"`
 }
 
 type ExternalTemplate struct {
@@ -77,6 +403,7 @@ type ExternalTemplate struct {
 // Organization represents GitHub organization
 type Organization struct {
 	Organization OrganizationStructure `yaml:"organization"`
+	Stale        StaleThresholds       `yaml:"stale"`
 }
 
 // OrganizationStructure has information about particular
@@ -100,5 +427,102 @@ func ReadConfiguration() Configuration {
 	if err != nil {
 		log.Fatalf("Error while parsing the config file %v, Err: %v", configFile, err)
 	}
+	config.defaultFeedLinks()
+	err = config.validate()
+	if err != nil {
+		log.Fatalf("Invalid config file %v, Err: %v", configFile, err)
+	}
 	return config
 }
+
+// reportOutputs are the outputs of every report
+func (config *Configuration) reportOutputs() map[string][]ReportOutput {
+	return map[string][]ReportOutput{
+		PullRequestReport:     config.PullRequests.PROutputs,
+		ReleaseReport:         config.Releases.ReleaseOutputs,
+		IssueReport:           config.Issues.IssueOutputs,
+		DiscussionReport:      config.Discussions.DiscussionOutputs,
+		CycleTimeReport:       config.PullRequests.PRCycleTime.Outputs,
+		ContributorReport:     config.Contributors.ContributorOutputs,
+		CommitReport:          config.Commits.CommitOutputs,
+		SecurityReport:        config.Security.SecurityOutputs,
+		MilestoneReport:       config.Milestones.MilestoneOutputs,
+		StaleReport:           config.Stale.StaleOutputs,
+		ScorecardReport:       config.Scorecard.ScorecardOutputs,
+		ReleaseTimelineReport: config.Releases.ReleaseTimeline.Outputs,
+	}
+}
+
+// defaultFeedLinks sets the link of the feeds which have none to the
+// site, or to the first organization on GitHub, RSS needs a link
+func (config *Configuration) defaultFeedLinks() {
+	link := config.Site.BaseURL
+	if link == "" && len(config.GlobalConfiguration.Organizations) != 0 {
+		link = "https://github.com/" + config.GlobalConfiguration.Organizations[0].Organization.Github
+	}
+	// the slices share their outputs with the configuration
+	for _, outputs := range config.reportOutputs() {
+		for index := range outputs {
+			if isFeed(outputs[index].Format) && outputs[index].Link == "" {
+				outputs[index].Link = link
+			}
+		}
+	}
+}
+
+func isFeed(format string) bool {
+	return format == FormatAtom || format == FormatRSS || format == FormatJSONFeed
+}
+
+// validate rejects the settings which would only fail or produce
+// empty files once the reports are fetched
+func (config Configuration) validate() error {
+	// these reports have no items, a table would only have the header
+	noItems := map[string][]ReportOutput{
+		ContributorReport:     config.Contributors.ContributorOutputs,
+		ScorecardReport:       config.Scorecard.ScorecardOutputs,
+		ReleaseTimelineReport: config.Releases.ReleaseTimeline.Outputs,
+		MilestoneReport:       config.Milestones.MilestoneOutputs,
+	}
+	for kind, outputs := range noItems {
+		for _, output := range outputs {
+			if output.Format == FormatCSV || output.Format == FormatTSV {
+				return fmt.Errorf("the %v report has no items to export as %v, output %v", kind, output.Format, output.Path)
+			}
+		}
+	}
+	externalTemplates := map[string]ElementExternalTemplate{
+		PullRequestReport: config.PullRequests.PRExternalTemplate,
+		ReleaseReport:     config.Releases.ReleaseExternalTemplate,
+		IssueReport:       config.Issues.IssueExternalTemplate,
+		DiscussionReport:  config.Discussions.DiscussionExternalTemplate,
+	}
+	for kind, externalTemplate := range externalTemplates {
+		switch externalTemplate.FrontMatter {
+		case "", FrontMatterYAML, FrontMatterTOML:
+		default:
+			return fmt.Errorf("unknown front matter %q of the %v external template, use %q or %q",
+				externalTemplate.FrontMatter, kind, FrontMatterYAML, FrontMatterTOML)
+		}
+		switch externalTemplate.ContentConvention {
+		case "", ContentConventionHugo, ContentConventionJekyll:
+		default:
+			return fmt.Errorf("unknown content convention %q of the %v external template, use %q or %q",
+				externalTemplate.ContentConvention, kind, ContentConventionHugo, ContentConventionJekyll)
+		}
+	}
+	for kind, outputs := range config.reportOutputs() {
+		for _, output := range outputs {
+			if isFeed(output.Format) && output.Link == "" {
+				return fmt.Errorf("the %v feed %v has no link, set it or the site base-url", kind, output.Path)
+			}
+		}
+	}
+	switch config.Publishers.Mastodon.Mode {
+	case "", MastodonModeStatus, MastodonModeThread:
+	default:
+		return fmt.Errorf("unknown mastodon mode %q, use %q or %q",
+			config.Publishers.Mastodon.Mode, MastodonModeStatus, MastodonModeThread)
+	}
+	return nil
+}
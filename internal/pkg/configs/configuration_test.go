@@ -0,0 +1,113 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package configs
+
+import "testing"
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Configuration
+		wantErr bool
+	}{
+		{
+			name: "default",
+		},
+		{
+			name:   "mastodon thread",
+			config: Configuration{Publishers: PublisherConfiguration{Mastodon: MastodonConfiguration{Mode: MastodonModeThread}}},
+		},
+		{
+			name:    "unknown mastodon mode",
+			config:  Configuration{Publishers: PublisherConfiguration{Mastodon: MastodonConfiguration{Mode: "Thread"}}},
+			wantErr: true,
+		},
+		{
+			name:   "csv of the issues",
+			config: Configuration{Issues: IssueConfiguration{IssueOutputs: []ReportOutput{{Format: FormatCSV, Path: "issues.csv"}}}},
+		},
+		{
+			name:    "csv of the contributors",
+			config:  Configuration{Contributors: ContributorConfiguration{ContributorOutputs: []ReportOutput{{Format: FormatCSV, Path: "contributors.csv"}}}},
+			wantErr: true,
+		},
+		{
+			name:    "tsv of the milestones",
+			config:  Configuration{Milestones: MilestoneConfiguration{MilestoneOutputs: []ReportOutput{{Format: FormatTSV, Path: "milestones.tsv"}}}},
+			wantErr: true,
+		},
+		{
+			name:    "feed without a link",
+			config:  Configuration{Releases: ReleaseConfiguration{ReleaseOutputs: []ReportOutput{{Format: FormatRSS, Path: "releases.xml"}}}},
+			wantErr: true,
+		},
+		{
+			name:   "hugo content with a toml front matter",
+			config: Configuration{Releases: ReleaseConfiguration{ReleaseExternalTemplate: ElementExternalTemplate{FrontMatter: FrontMatterTOML, ContentConvention: ContentConventionHugo}}},
+		},
+		{
+			name:    "unknown front matter",
+			config:  Configuration{PullRequests: PullRequestConfiguration{PRExternalTemplate: ElementExternalTemplate{FrontMatter: "json"}}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown content convention",
+			config:  Configuration{Issues: IssueConfiguration{IssueExternalTemplate: ElementExternalTemplate{ContentConvention: "gatsby"}}},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.config.validate()
+			if (err != nil) != test.wantErr {
+				t.Errorf("got %v, want an error: %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestDefaultFeedLinks(t *testing.T) {
+	config := Configuration{
+		GlobalConfiguration: GlobalConfiguration{Organizations: []Organization{
+			{Organization: OrganizationStructure{Github: "hyperledger"}},
+		}},
+		Issues: IssueConfiguration{IssueOutputs: []ReportOutput{
+			{Format: FormatRSS, Path: "issues.xml"},
+			{Format: FormatAtom, Path: "issues.atom.xml", Link: "https://example.org/issues"},
+			{Format: FormatMarkdown, Path: "issues.md"},
+		}},
+	}
+	config.defaultFeedLinks()
+	links := []string{}
+	for _, output := range config.Issues.IssueOutputs {
+		links = append(links, output.Link)
+	}
+	want := []string{"https://github.com/hyperledger", "https://example.org/issues", ""}
+	for index := range want {
+		if links[index] != want[index] {
+			t.Errorf("got the links %q, want %q", links, want)
+			break
+		}
+	}
+
+	config.Site.BaseURL = "https://updates.example.org"
+	config.Issues.IssueOutputs[0].Link = ""
+	config.defaultFeedLinks()
+	if link := config.Issues.IssueOutputs[0].Link; link != config.Site.BaseURL {
+		t.Errorf("got the link %q, want the site", link)
+	}
+}
@@ -33,4 +33,115 @@ const (
 	ReleaseTemplateFile = "RELEASE_TEMPLATE_FILE"
 	// IssueTemplateFile env variable
 	IssueTemplateFile = "ISSUE_TEMPLATE_FILE"
+	// ContributorSummaryFilePath env variable
+	ContributorSummaryFilePath = "CONTRIBUTOR_SUMMARY_FILE_PATH"
+	// ContributorTemplateFile env variable
+	ContributorTemplateFile = "CONTRIBUTOR_TEMPLATE_FILE"
+	// CycleTimeSummaryFilePath env variable
+	CycleTimeSummaryFilePath = "CYCLE_TIME_SUMMARY_FILE_PATH"
+	// CycleTimeTemplateFile env variable
+	CycleTimeTemplateFile = "CYCLE_TIME_TEMPLATE_FILE"
+	// StaleSummaryFilePath env variable
+	StaleSummaryFilePath = "STALE_SUMMARY_FILE_PATH"
+	// StaleTemplateFile env variable
+	StaleTemplateFile = "STALE_TEMPLATE_FILE"
+	// ScorecardSummaryFilePath env variable
+	ScorecardSummaryFilePath = "SCORECARD_SUMMARY_FILE_PATH"
+	// ScorecardTemplateFile env variable
+	ScorecardTemplateFile = "SCORECARD_TEMPLATE_FILE"
+	// ReleaseTimelineSummaryFilePath env variable
+	ReleaseTimelineSummaryFilePath = "RELEASE_TIMELINE_SUMMARY_FILE_PATH"
+	// ReleaseTimelineTemplateFile env variable
+	ReleaseTimelineTemplateFile = "RELEASE_TIMELINE_TEMPLATE_FILE"
+	// CommitSummaryFilePath env variable
+	CommitSummaryFilePath = "COMMIT_SUMMARY_FILE_PATH"
+	// CommitTemplateFile env variable
+	CommitTemplateFile = "COMMIT_TEMPLATE_FILE"
+	// DiscussionSummaryFilePath env variable
+	DiscussionSummaryFilePath = "DISCUSSION_SUMMARY_FILE_PATH"
+	// DiscussionTemplateFile env variable
+	DiscussionTemplateFile = "DISCUSSION_TEMPLATE_FILE"
+	// SecuritySummaryFilePath env variable
+	SecuritySummaryFilePath = "SECURITY_SUMMARY_FILE_PATH"
+	// SecurityTemplateFile env variable
+	SecurityTemplateFile = "SECURITY_TEMPLATE_FILE"
+	// MilestoneSummaryFilePath env variable
+	MilestoneSummaryFilePath = "MILESTONE_SUMMARY_FILE_PATH"
+	// MilestoneTemplateFile env variable
+	MilestoneTemplateFile = "MILESTONE_TEMPLATE_FILE"
+	// SMTPPassword env variable for the email delivery
+	SMTPPassword = "SMTP_PASSWORD"
+	// SlackWebhookURL env variable for the Slack publisher
+	SlackWebhookURL = "SLACK_WEBHOOK_URL"
+	// DiscordWebhookURL env variable for the Discord publisher
+	DiscordWebhookURL = "DISCORD_WEBHOOK_URL"
+	// MastodonAccessToken env variable for the Mastodon announcements
+	MastodonAccessToken = "MASTODON_ACCESS_TOKEN"
+)
+
+const (
+	// PullRequestReport identifies the pull request report
+	PullRequestReport = "pull-requests"
+	// ReleaseReport identifies the release report
+	ReleaseReport = "releases"
+	// IssueReport identifies the issue report
+	IssueReport = "issues"
+	// ContributorReport identifies the contributor statistics report
+	ContributorReport = "contributors"
+	// CycleTimeReport identifies the PR cycle time report
+	CycleTimeReport = "cycle-time"
+	// StaleReport identifies the stale PR and abandoned issue report
+	StaleReport = "stale"
+	// ScorecardReport identifies the repository health scorecard
+	ScorecardReport = "scorecard"
+	// ReleaseTimelineReport identifies the release cadence timeline
+	ReleaseTimelineReport = "release-timeline"
+	// CommitReport identifies the default branch commit report
+	CommitReport = "commits"
+	// DiscussionReport identifies the GitHub Discussions report
+	DiscussionReport = "discussions"
+	// SecurityReport identifies the security advisory digest
+	SecurityReport = "security"
+	// MilestoneReport identifies the milestone progress report
+	MilestoneReport = "milestones"
+)
+
+const (
+	// FormatHTML renders the summary with an html template
+	FormatHTML = "html"
+	// FormatMarkdown renders the summary with a markdown template
+	FormatMarkdown = "markdown"
+	// FormatAtom writes the report as an Atom 1.0 feed
+	FormatAtom = "atom"
+	// FormatRSS writes the report as an RSS 2.0 feed
+	FormatRSS = "rss"
+	// FormatJSONFeed writes the report as a JSON Feed 1.1
+	FormatJSONFeed = "json-feed"
+	// FormatCSV exports one comma separated row per item
+	FormatCSV = "csv"
+	// FormatTSV exports one tab separated row per item
+	FormatTSV = "tsv"
+)
+
+const (
+	// MastodonModeStatus posts one status per release, the default
+	MastodonModeStatus = "status"
+	// MastodonModeThread posts the releases as replies to a status
+	// introducing them
+	MastodonModeThread = "thread"
+)
+
+const (
+	// FrontMatterYAML starts the external files with a YAML front matter
+	FrontMatterYAML = "yaml"
+	// FrontMatterTOML starts the external files with a TOML front matter
+	FrontMatterTOML = "toml"
+)
+
+const (
+	// ContentConventionHugo names the external files by their slug
+	ContentConventionHugo = "hugo"
+	// ContentConventionJekyll names the external files by their date
+	// and slug, as the Jekyll posts
+	ContentConventionJekyll = "jekyll"
 )
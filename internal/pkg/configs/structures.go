@@ -17,18 +17,42 @@
 package configs
 
 import (
+	"time"
+
 	"github.com/google/go-github/v33/github"
 )
 
 type RepositoryStructure struct {
-	Name string
-	Link string
+	Name  string
+	Link  string
+	Stars int
+}
+
+// RepositoryDetails is the metadata of a repository
+type RepositoryDetails struct {
+	ID            int64
+	Name          string
+	Stars         int
+	DefaultBranch string
+	Archived      bool
+}
+
+// Engagement has the activity counts of a PR which are not in the
+// PR list response
+type Engagement struct {
+	Comments  int `json:"comments"`
+	Reactions int `json:"reactions"`
+	Reviews   int `json:"reviews"`
 }
 
 type ExternalPRDetails struct {
 	Organization OrganizationStructure
 	Repository   RepositoryStructure
 	PRs          []github.PullRequest
+	Engagement   map[int]Engagement
+	// NewContributors has the logins of the authors who are new to
+	// the repository, nil when they were not looked up
+	NewContributors map[string]bool
 }
 
 type ExternalIssueDetails struct {
@@ -43,37 +67,476 @@ type ExternalReleaseDetails struct {
 	Releases     []github.RepositoryRelease
 }
 
+// ExternalDiscussionDetails has the discussions of a repository by
+// category, for the external templates
+type ExternalDiscussionDetails struct {
+	Organization OrganizationStructure
+	Repository   RepositoryStructure
+	Categories   []DiscussionCategory
+}
+
 // PullRequestDetails contains organization name
 // and PrLists
 type PullRequestDetails struct {
-	Organization string   `json:"organization,omitempty"`
-	PrRepoLists  []PrList `json:"prlists,omitempty"`
+	Organization    string           `json:"organization,omitempty"`
+	PrRepoLists     []PrList         `json:"prlists,omitempty"`
+	NewContributors []NewContributor `json:"newContributors,omitempty"`
+	Comparison      *Delta           `json:"comparison,omitempty"`
+	Charts          *Charts          `json:"charts,omitempty"`
+	Days            int              `json:"days,omitempty"`
+	// ListedRepositories are the names of all the repositories of the
+	// organization, with or without pull requests
+	ListedRepositories []string `json:"listedRepositories,omitempty"`
+}
+
+// Charts has the file names of the SVG charts of an organization,
+// relative to the summary. A chart which was not drawn is empty.
+type Charts struct {
+	PRsPerRepository string `json:"prsPerRepository,omitempty"`
+	WeeklyActivity   string `json:"weeklyActivity,omitempty"`
+	ReleaseTimeline  string `json:"releaseTimeline,omitempty"`
+}
+
+// NewContributor is the author of a first PR, with the PR
+type NewContributor struct {
+	Login       string    `json:"login"`
+	ProfileURL  string    `json:"profileUrl"`
+	AvatarURL   string    `json:"avatarUrl"`
+	Repository  string    `json:"repository"`
+	Number      int       `json:"number"`
+	Title       string    `json:"title"`
+	URL         string    `json:"url"`
+	CreatedAt   time.Time `json:"createdAt"`
+	Merged      bool      `json:"merged"`
+	Association string    `json:"association"`
 }
 
 // PrList contains repository name
 // and the associated PRs
 type PrList struct {
-	Repository string               `json:"repository,omitempty"`
-	PRs        []github.PullRequest `json:"prs,omitempty"`
+	Repository   string               `json:"repository,omitempty"`
+	RepositoryID int64                `json:"repositoryId,omitempty"`
+	Stars        int                  `json:"stars,omitempty"`
+	PRs          []github.PullRequest `json:"prs,omitempty"`
+	// Engagement of the PRs by number, set when it is fetched
+	Engagement map[int]Engagement `json:"engagement,omitempty"`
+	// CycleTime of the PRs by number and of the repository, set
+	// when it is enabled
+	CycleTime        map[int]CycleTime `json:"cycleTime,omitempty"`
+	CycleTimeSummary *CycleTimeSummary `json:"cycleTimeSummary,omitempty"`
+}
+
+// CycleTime has the review latency of a PR, the hours are not set
+// when the PR has no review or is not merged yet. A review round is
+// a commit of the PR which got reviewed.
+type CycleTime struct {
+	Reviews                int      `json:"reviews"`
+	ReviewRounds           int      `json:"reviewRounds"`
+	TimeToFirstReviewHours *float64 `json:"timeToFirstReviewHours,omitempty"`
+	TimeToMergeHours       *float64 `json:"timeToMergeHours,omitempty"`
+}
+
+// CycleTimeSummary is the distribution of the cycle time of the PRs
+// of a repository
+type CycleTimeSummary struct {
+	PRs               int         `json:"prs"`
+	Merged            int         `json:"merged"`
+	TimeToFirstReview Percentiles `json:"timeToFirstReviewHours"`
+	TimeToMerge       Percentiles `json:"timeToMergeHours"`
+	ReviewRounds      Percentiles `json:"reviewRounds"`
+	Reviews           Percentiles `json:"reviews"`
+}
+
+// Percentiles of the values, Count is the number of values
+type Percentiles struct {
+	Count  int     `json:"count"`
+	Median float64 `json:"median"`
+	P90    float64 `json:"p90"`
 }
 
 type ReleaseDetails struct {
 	Organization     string        `json:"organization,omitempty"`
 	ReleaseRepoLists []ReleaseList `json:"releaseList,omitempty"`
+	Comparison       *Delta        `json:"comparison,omitempty"`
+	Charts           *Charts       `json:"charts,omitempty"`
+	Days             int           `json:"days,omitempty"`
+	// ListedRepositories are the names of all the repositories of the
+	// organization, with or without releases
+	ListedRepositories []string `json:"listedRepositories,omitempty"`
 }
 
 type IssueDetails struct {
 	Organization string      `json:"organization,omitempty"`
 	IssueLists   []IssueList `json:"issueLists,omitempty"`
+	Comparison   *Delta      `json:"comparison,omitempty"`
+	Days         int         `json:"days,omitempty"`
+	// ListedRepositories are the names of all the repositories of the
+	// organization, with or without issues
+	ListedRepositories []string `json:"listedRepositories,omitempty"`
 }
 
 type ReleaseList struct {
-	Repository string                     `json:"repository,omitempty"`
-	Releases   []github.RepositoryRelease `json:"releases,omitempty"`
+	Repository   string                     `json:"repository,omitempty"`
+	RepositoryID int64                      `json:"repositoryId,omitempty"`
+	Stars        int                        `json:"stars,omitempty"`
+	Releases     []github.RepositoryRelease `json:"releases,omitempty"`
 }
 
 type IssueList struct {
-	Repository string         `json:"repository,omitempty"`
-	Labels     []string       `json:"labels,omitempty"`
-	Issues     []github.Issue `json:"issues,omitempty"`
+	Repository   string         `json:"repository,omitempty"`
+	RepositoryID int64          `json:"repositoryId,omitempty"`
+	Stars        int            `json:"stars,omitempty"`
+	Labels       []string       `json:"labels,omitempty"`
+	Issues       []github.Issue `json:"issues,omitempty"`
+}
+
+// ActivityList has what happened in a repository during the
+// reported days, the PRs updated, their reviews by PR number and
+// the issues opened
+type ActivityList struct {
+	Repository string                             `json:"repository,omitempty"`
+	PRs        []github.PullRequest               `json:"prs,omitempty"`
+	Reviews    map[int][]github.PullRequestReview `json:"reviews,omitempty"`
+	Issues     []github.Issue                     `json:"issues,omitempty"`
+}
+
+// ContributorDetails has the statistics of the authors of an
+// organization, in total and by repository
+type ContributorDetails struct {
+	Organization     string             `json:"organization,omitempty"`
+	Contributors     []ContributorStats `json:"contributors,omitempty"`
+	ContributorLists []ContributorList  `json:"contributorLists,omitempty"`
+}
+
+type ContributorList struct {
+	Repository   string             `json:"repository,omitempty"`
+	Contributors []ContributorStats `json:"contributors,omitempty"`
+}
+
+// ContributorStats counts the activity of an author
+type ContributorStats struct {
+	Login        string `json:"login"`
+	ProfileURL   string `json:"profileUrl"`
+	AvatarURL    string `json:"avatarUrl"`
+	PRsOpened    int    `json:"prsOpened"`
+	PRsMerged    int    `json:"prsMerged"`
+	ReviewsGiven int    `json:"reviewsGiven"`
+	IssuesOpened int    `json:"issuesOpened"`
+}
+
+// Total of the contributions, used to order the contributors
+func (s ContributorStats) Total() int {
+	return s.PRsOpened + s.PRsMerged + s.ReviewsGiven + s.IssuesOpened
+}
+
+// StaleDetails has the stuck PRs and issues of an organization
+type StaleDetails struct {
+	Organization string          `json:"organization,omitempty"`
+	Thresholds   StaleThresholds `json:"thresholds"`
+	StaleLists   []StaleList     `json:"staleLists,omitempty"`
+}
+
+// StaleList has the stuck PRs and issues of a repository
+type StaleList struct {
+	Repository      string       `json:"repository,omitempty"`
+	InactivePRs     []StalePR    `json:"inactivePrs,omitempty"`
+	UnreviewedPRs   []StalePR    `json:"unreviewedPrs,omitempty"`
+	AbandonedIssues []StaleIssue `json:"abandonedIssues,omitempty"`
+}
+
+// StalePR is an open PR with the days it has been idle
+type StalePR struct {
+	PR       github.PullRequest `json:"pr"`
+	IdleDays int                `json:"idleDays"`
+}
+
+// StaleIssue is an assigned issue with the last time one of the
+// assignees was active on it
+type StaleIssue struct {
+	Issue              github.Issue `json:"issue"`
+	AssigneeLastActive time.Time    `json:"assigneeLastActive"`
+	IdleDays           int          `json:"idleDays"`
+}
+
+// Delta compares the number of items of an organization with the
+// previous run, Since is when the previous run was generated.
+// Percent is not set when there were no items before.
+type Delta struct {
+	Since        time.Time         `json:"since"`
+	Current      int               `json:"current"`
+	Previous     int               `json:"previous"`
+	Change       int               `json:"change"`
+	Percent      *float64          `json:"percent,omitempty"`
+	New          bool              `json:"new,omitempty"`
+	Repositories []RepositoryDelta `json:"repositories,omitempty"`
+}
+
+// RepositoryDelta compares the number of items of a repository
+// with the previous run. New repositories were not listed in the
+// organization before and the removed ones are not listed anymore.
+type RepositoryDelta struct {
+	Repository string   `json:"repository"`
+	Current    int      `json:"current"`
+	Previous   int      `json:"previous"`
+	Change     int      `json:"change"`
+	Percent    *float64 `json:"percent,omitempty"`
+	New        bool     `json:"new,omitempty"`
+	Removed    bool     `json:"removed,omitempty"`
+}
+
+// HealthDetails has the scorecards of the repositories of an
+// organization
+type HealthDetails struct {
+	Organization string             `json:"organization,omitempty"`
+	Repositories []RepositoryHealth `json:"repositories,omitempty"`
+}
+
+// RepositoryHealth is the scorecard of a repository
+type RepositoryHealth struct {
+	Repository string        `json:"repository"`
+	URL        string        `json:"url"`
+	Signals    HealthSignals `json:"signals"`
+	Score      HealthScore   `json:"score"`
+}
+
+// HealthSignals are gathered over the days of the scorecard, the
+// mergers are the people who merged the PRs
+type HealthSignals struct {
+	Commits          int        `json:"commits"`
+	MergedPRs        int        `json:"mergedPrs"`
+	Mergers          int        `json:"mergers"`
+	Releases         int        `json:"releases"`
+	LastRelease      *time.Time `json:"lastRelease,omitempty"`
+	IssuesOpened     int        `json:"issuesOpened"`
+	IssuesClosed     int        `json:"issuesClosed"`
+	Readme           bool       `json:"readme"`
+	License          bool       `json:"license"`
+	Contributing     bool       `json:"contributing"`
+	Security         bool       `json:"security"`
+	BranchProtection bool       `json:"branchProtection"`
+}
+
+// HealthScore has the points of every signal, Total is out of 100
+type HealthScore struct {
+	Total            float64 `json:"total"`
+	Activity         float64 `json:"activity"`
+	ReleaseCadence   float64 `json:"releaseCadence"`
+	IssueGrowth      float64 `json:"issueGrowth"`
+	BusFactor        float64 `json:"busFactor"`
+	CommunityFiles   float64 `json:"communityFiles"`
+	BranchProtection float64 `json:"branchProtection"`
+}
+
+// TimelineDetails has the release cadence of the repositories of an
+// organization, the positions of the releases are set along the
+// range from the first release of the organization to now
+type TimelineDetails struct {
+	Organization string               `json:"organization,omitempty"`
+	SLADays      int                  `json:"slaDays"`
+	Since        *time.Time           `json:"since,omitempty"`
+	Repositories []RepositoryTimeline `json:"repositories,omitempty"`
+}
+
+// RepositoryTimeline is the release cadence of a repository
+type RepositoryTimeline struct {
+	Repository         string          `json:"repository"`
+	URL                string          `json:"url"`
+	Releases           int             `json:"releases"`
+	FirstRelease       *time.Time      `json:"firstRelease,omitempty"`
+	LastRelease        *time.Time      `json:"lastRelease,omitempty"`
+	AverageDaysBetween *float64        `json:"averageDaysBetween,omitempty"`
+	DaysSinceLast      *int            `json:"daysSinceLast,omitempty"`
+	OverSLA            bool            `json:"overSla"`
+	Series             []TimelinePoint `json:"series"`
+}
+
+// TimelinePoint is a release on the timeline, Position is in
+// percent of the timeline of the organization
+type TimelinePoint struct {
+	Date              time.Time `json:"date"`
+	Tag               string    `json:"tag"`
+	Name              string    `json:"name,omitempty"`
+	URL               string    `json:"url"`
+	Prerelease        bool      `json:"prerelease,omitempty"`
+	DaysSincePrevious *float64  `json:"daysSincePrevious,omitempty"`
+	Position          float64   `json:"position"`
+}
+
+// CommitDetails has the commits pushed to the default branch of the
+// repositories of an organization
+type CommitDetails struct {
+	Organization string       `json:"organization,omitempty"`
+	CommitLists  []CommitList `json:"commitLists,omitempty"`
+}
+
+// CommitList has the commits of a repository, the newest first, and
+// their totals. The authors count the co-authors as well. The files
+// and the lines are only counted when the files are fetched.
+type CommitList struct {
+	Repository   string   `json:"repository"`
+	Branch       string   `json:"branch"`
+	Commits      []Commit `json:"commits,omitempty"`
+	Authors      int      `json:"authors"`
+	FilesChanged int      `json:"filesChanged"`
+	Additions    int      `json:"additions"`
+	Deletions    int      `json:"deletions"`
+}
+
+// Commit is a commit of the default branch, Subject is the first
+// line of its message
+type Commit struct {
+	SHA          string         `json:"sha"`
+	URL          string         `json:"url"`
+	Subject      string         `json:"subject"`
+	Author       CommitAuthor   `json:"author"`
+	CoAuthors    []CommitAuthor `json:"coAuthors,omitempty"`
+	Date         time.Time      `json:"date"`
+	FilesChanged int            `json:"filesChanged"`
+	Additions    int            `json:"additions"`
+	Deletions    int            `json:"deletions"`
+}
+
+// CommitAuthor is the git author of a commit, Login is only set when
+// the email is linked to a GitHub account
+type CommitAuthor struct {
+	Name       string `json:"name"`
+	Email      string `json:"email,omitempty"`
+	Login      string `json:"login,omitempty"`
+	ProfileURL string `json:"profileUrl,omitempty"`
+	AvatarURL  string `json:"avatarUrl,omitempty"`
+}
+
+// DiscussionDetails has the discussions of the repositories of an
+// organization
+type DiscussionDetails struct {
+	Organization    string           `json:"organization,omitempty"`
+	DiscussionLists []DiscussionList `json:"discussionLists,omitempty"`
+}
+
+// DiscussionList has the discussions of a repository grouped by
+// category
+type DiscussionList struct {
+	Repository string               `json:"repository"`
+	Categories []DiscussionCategory `json:"categories,omitempty"`
+}
+
+// DiscussionCategory has the discussions started and the questions
+// answered during the reported days, a discussion may be in both
+type DiscussionCategory struct {
+	Name       string       `json:"name"`
+	Emoji      string       `json:"emoji,omitempty"`
+	Answerable bool         `json:"answerable"`
+	New        []Discussion `json:"new,omitempty"`
+	Answered   []Discussion `json:"answered,omitempty"`
+}
+
+// Discussion is a GitHub discussion, the answer is only set for the
+// answered questions
+type Discussion struct {
+	Number          int        `json:"number"`
+	Title           string     `json:"title"`
+	URL             string     `json:"url"`
+	Category        string     `json:"category"`
+	CategoryEmoji   string     `json:"categoryEmoji,omitempty"`
+	Answerable      bool       `json:"answerable"`
+	Author          string     `json:"author"`
+	AuthorURL       string     `json:"authorUrl,omitempty"`
+	AuthorAvatarURL string     `json:"authorAvatarUrl,omitempty"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	UpdatedAt       time.Time  `json:"updatedAt"`
+	Comments        int        `json:"comments"`
+	Upvotes         int        `json:"upvotes"`
+	AnswerChosenAt  *time.Time `json:"answerChosenAt,omitempty"`
+	AnswerURL       string     `json:"answerUrl,omitempty"`
+	AnsweredBy      string     `json:"answeredBy,omitempty"`
+	AnsweredByURL   string     `json:"answeredByUrl,omitempty"`
+}
+
+// RepositoryDiscussions are the discussions of a repository updated
+// during the reported days, as listed by the client
+type RepositoryDiscussions struct {
+	Repository  string       `json:"repository"`
+	Discussions []Discussion `json:"discussions,omitempty"`
+}
+
+// SecurityDetails has the security digest of the repositories of an
+// organization
+type SecurityDetails struct {
+	Organization  string         `json:"organization,omitempty"`
+	SecurityLists []SecurityList `json:"securityLists,omitempty"`
+}
+
+// SecurityList has the advisories published for a repository, the
+// most severe first, and its releases and PRs fixing a vulnerability
+type SecurityList struct {
+	Repository string        `json:"repository"`
+	Advisories []Advisory    `json:"advisories,omitempty"`
+	Releases   []SecurityFix `json:"releases,omitempty"`
+	PRs        []SecurityFix `json:"prs,omitempty"`
+}
+
+// Advisory is a published repository security advisory
+type Advisory struct {
+	GHSAID          string                  `json:"ghsaId"`
+	CVEID           string                  `json:"cveId,omitempty"`
+	Summary         string                  `json:"summary"`
+	URL             string                  `json:"url"`
+	Severity        string                  `json:"severity"`
+	PublishedAt     time.Time               `json:"publishedAt"`
+	Vulnerabilities []AdvisoryVulnerability `json:"vulnerabilities,omitempty"`
+}
+
+// AdvisoryVulnerability is a package affected by an advisory
+type AdvisoryVulnerability struct {
+	Ecosystem        string `json:"ecosystem,omitempty"`
+	Package          string `json:"package,omitempty"`
+	AffectedVersions string `json:"affectedVersions,omitempty"`
+	PatchedVersions  string `json:"patchedVersions,omitempty"`
+}
+
+// SecurityFix is a release or a PR fixing a vulnerability. The
+// severity and the versions come from the advisory it refers to,
+// the patched version of a release is its tag otherwise.
+type SecurityFix struct {
+	Title            string     `json:"title"`
+	URL              string     `json:"url"`
+	Number           int        `json:"number,omitempty"`
+	TagName          string     `json:"tagName,omitempty"`
+	Author           string     `json:"author"`
+	AuthorURL        string     `json:"authorUrl,omitempty"`
+	Date             *time.Time `json:"date,omitempty"`
+	References       []string   `json:"references,omitempty"`
+	Severity         string     `json:"severity,omitempty"`
+	AffectedVersions string     `json:"affectedVersions,omitempty"`
+	PatchedVersions  string     `json:"patchedVersions,omitempty"`
+}
+
+// MilestoneDetails has the open milestones of the repositories of an
+// organization
+type MilestoneDetails struct {
+	Organization   string          `json:"organization,omitempty"`
+	MilestoneLists []MilestoneList `json:"milestoneLists,omitempty"`
+}
+
+// MilestoneList has the open milestones of a repository, the first
+// due first
+type MilestoneList struct {
+	Repository string      `json:"repository"`
+	Milestones []Milestone `json:"milestones,omitempty"`
+}
+
+// Milestone is the progress of an open milestone, the issues count
+// the PRs as GitHub does. ClosedRecently are the ones closed during
+// the reported days.
+type Milestone struct {
+	Number          int        `json:"number"`
+	Title           string     `json:"title"`
+	URL             string     `json:"url"`
+	DueOn           *time.Time `json:"dueOn,omitempty"`
+	OpenIssues      int        `json:"openIssues"`
+	ClosedIssues    int        `json:"closedIssues"`
+	ClosedRecently  int        `json:"closedRecently"`
+	PercentComplete float64    `json:"percentComplete"`
+	DaysLeft        *int       `json:"daysLeft,omitempty"`
+	Overdue         bool       `json:"overdue"`
 }
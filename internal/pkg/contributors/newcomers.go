@@ -0,0 +1,142 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package contributors finds out who contributed to the
+// organizations during the reported days
+package contributors
+
+import (
+	"fmt"
+	client2 "github-updates/internal/pkg/client"
+	"github-updates/internal/pkg/configs"
+	"log"
+	"sort"
+)
+
+// Scopes of the new contributors
+const (
+	ScopeRepository   = "repository"
+	ScopeOrganization = "organization"
+)
+
+// Author associations of GitHub which decide without a lookup
+const (
+	firstTimer              = "FIRST_TIMER"
+	firstTimeContributor    = "FIRST_TIME_CONTRIBUTOR"
+	associationOwner        = "OWNER"
+	associationMember       = "MEMBER"
+	associationCollaborator = "COLLABORATOR"
+)
+
+// NewContributors returns the authors of the PRs who had no PR
+// merged in the scope before their first PR of the reported days.
+// The author association of the PR answers for first timers and
+// members, the search of the merged PRs is only used for the rest.
+// Bots are never new contributors.
+func NewContributors(
+	client client2.GHClientInterface,
+	org string,
+	prLists []configs.PrList,
+	scope string,
+) ([]configs.NewContributor, error) {
+	switch scope {
+	case "":
+		scope = ScopeRepository
+	case ScopeRepository, ScopeOrganization:
+	default:
+		return nil, fmt.Errorf("unknown new contributors scope %v", scope)
+	}
+
+	// the earliest PR of every author in the scope
+	first := map[string]configs.NewContributor{}
+	var keys []string
+	for _, prList := range prLists {
+		for _, pr := range prList.PRs {
+			user := pr.GetUser()
+			if user.GetLogin() == "" || IsBot(user) {
+				continue
+			}
+			key := user.GetLogin()
+			if scope == ScopeRepository {
+				key = prList.Repository + "/" + key
+			}
+			existing, found := first[key]
+			if found && !pr.GetCreatedAt().Before(existing.CreatedAt) {
+				continue
+			}
+			if !found {
+				keys = append(keys, key)
+			}
+			first[key] = configs.NewContributor{
+				Login:       user.GetLogin(),
+				ProfileURL:  user.GetHTMLURL(),
+				AvatarURL:   user.GetAvatarURL(),
+				Repository:  prList.Repository,
+				Number:      pr.GetNumber(),
+				Title:       pr.GetTitle(),
+				URL:         pr.GetHTMLURL(),
+				CreatedAt:   pr.GetCreatedAt(),
+				Merged:      pr.MergedAt != nil,
+				Association: pr.GetAuthorAssociation(),
+			}
+		}
+	}
+
+	var newContributors []configs.NewContributor
+	for _, key := range keys {
+		candidate := first[key]
+		isNew, err := isNewContributor(client, org, candidate, scope)
+		if err != nil {
+			return nil, err
+		}
+		if isNew {
+			newContributors = append(newContributors, candidate)
+		}
+	}
+	sort.SliceStable(newContributors, func(i, j int) bool {
+		return newContributors[i].CreatedAt.Before(newContributors[j].CreatedAt)
+	})
+	return newContributors, nil
+}
+
+func isNewContributor(
+	client client2.GHClientInterface,
+	org string,
+	candidate configs.NewContributor,
+	scope string,
+) (bool, error) {
+	switch candidate.Association {
+	case firstTimer:
+		return true, nil
+	case firstTimeContributor:
+		// only tells about the repository
+		if scope == ScopeRepository {
+			return true, nil
+		}
+	case associationOwner, associationMember, associationCollaborator:
+		return false, nil
+	}
+	repository := ""
+	if scope == ScopeRepository {
+		repository = candidate.Repository
+	}
+	log.Printf("Looking up the merged PRs of %v in %v", candidate.Login, org)
+	merged, err := client.MergedPRsBefore(org, repository, candidate.Login, candidate.CreatedAt)
+	if err != nil {
+		return false, err
+	}
+	return merged == 0, nil
+}
@@ -0,0 +1,120 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contributors
+
+import (
+	"github-updates/internal/pkg/configs"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v33/github"
+)
+
+// Statistics counts the PRs opened and merged, the reviews given and
+// the issues opened since the start date by every author of the
+// organization and of each repository. Reviews of the author on
+// their own PR are not counted, the bots and the excluded users are
+// left out as configured.
+func Statistics(
+	org string,
+	activityLists []configs.ActivityList,
+	startDate time.Time,
+	config configs.ContributorConfiguration,
+) configs.ContributorDetails {
+	excluded := func(user *github.User) bool {
+		if user.GetLogin() == "" || (config.ExcludeBots && IsBot(user)) {
+			return true
+		}
+		for _, login := range config.ExcludeUsers {
+			if strings.EqualFold(login, user.GetLogin()) {
+				return true
+			}
+		}
+		return false
+	}
+
+	organization := tally{}
+	details := configs.ContributorDetails{Organization: org}
+	for _, activity := range activityLists {
+		repository := tally{}
+		count := func(user *github.User, add func(*configs.ContributorStats)) {
+			if excluded(user) {
+				return
+			}
+			add(repository.of(user))
+			add(organization.of(user))
+		}
+		for _, pr := range activity.PRs {
+			author := pr.GetUser()
+			if !pr.GetCreatedAt().Before(startDate) {
+				count(author, func(s *configs.ContributorStats) { s.PRsOpened++ })
+			}
+			if pr.MergedAt != nil && !pr.GetMergedAt().Before(startDate) {
+				count(author, func(s *configs.ContributorStats) { s.PRsMerged++ })
+			}
+			for _, review := range activity.Reviews[pr.GetNumber()] {
+				if review.GetUser().GetLogin() == author.GetLogin() {
+					continue
+				}
+				count(review.GetUser(), func(s *configs.ContributorStats) { s.ReviewsGiven++ })
+			}
+		}
+		for _, issue := range activity.Issues {
+			count(issue.GetUser(), func(s *configs.ContributorStats) { s.IssuesOpened++ })
+		}
+		if contributors := repository.sorted(); len(contributors) != 0 {
+			details.ContributorLists = append(details.ContributorLists, configs.ContributorList{
+				Repository:   activity.Repository,
+				Contributors: contributors,
+			})
+		}
+	}
+	details.Contributors = organization.sorted()
+	return details
+}
+
+// tally keeps the statistics by login
+type tally map[string]*configs.ContributorStats
+
+func (t tally) of(user *github.User) *configs.ContributorStats {
+	stats, found := t[user.GetLogin()]
+	if !found {
+		stats = &configs.ContributorStats{
+			Login:      user.GetLogin(),
+			ProfileURL: user.GetHTMLURL(),
+			AvatarURL:  user.GetAvatarURL(),
+		}
+		t[user.GetLogin()] = stats
+	}
+	return stats
+}
+
+// sorted returns the most active contributors first
+func (t tally) sorted() []configs.ContributorStats {
+	contributors := make([]configs.ContributorStats, 0, len(t))
+	for _, stats := range t {
+		contributors = append(contributors, *stats)
+	}
+	sort.Slice(contributors, func(i, j int) bool {
+		if contributors[i].Total() != contributors[j].Total() {
+			return contributors[i].Total() > contributors[j].Total()
+		}
+		return contributors[i].Login < contributors[j].Login
+	})
+	return contributors
+}
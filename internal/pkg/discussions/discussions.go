@@ -0,0 +1,116 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package discussions groups the GitHub Discussions of the
+// repositories by category
+package discussions
+
+import (
+	"github-updates/internal/pkg/configs"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Group keeps the discussions started and the questions answered
+// since the start, by category. The categories are matched by name
+// regardless of the case, all of them when none is given. The
+// repositories and the categories with no discussion left are
+// dropped.
+func Group(
+	repositoryDiscussions []configs.RepositoryDiscussions,
+	categories []string,
+	start time.Time,
+) []configs.DiscussionList {
+	var discussionLists []configs.DiscussionList
+	for _, repository := range repositoryDiscussions {
+		byCategory := map[string]*configs.DiscussionCategory{}
+		for _, discussion := range repository.Discussions {
+			if !matchesCategory(discussion.Category, categories) {
+				continue
+			}
+			isNew := !discussion.CreatedAt.Before(start)
+			isAnswered := discussion.AnswerChosenAt != nil && !discussion.AnswerChosenAt.Before(start)
+			if !isNew && !isAnswered {
+				continue
+			}
+			category, ok := byCategory[discussion.Category]
+			if !ok {
+				category = &configs.DiscussionCategory{
+					Name:       discussion.Category,
+					Emoji:      discussion.CategoryEmoji,
+					Answerable: discussion.Answerable,
+				}
+				byCategory[discussion.Category] = category
+			}
+			if isNew {
+				category.New = append(category.New, discussion)
+			}
+			if isAnswered {
+				category.Answered = append(category.Answered, discussion)
+			}
+		}
+		if len(byCategory) == 0 {
+			continue
+		}
+		discussionList := configs.DiscussionList{Repository: repository.Repository}
+		for _, category := range byCategory {
+			sort.SliceStable(category.New, func(i, j int) bool {
+				return category.New[i].CreatedAt.After(category.New[j].CreatedAt)
+			})
+			sort.SliceStable(category.Answered, func(i, j int) bool {
+				return category.Answered[i].AnswerChosenAt.After(*category.Answered[j].AnswerChosenAt)
+			})
+			discussionList.Categories = append(discussionList.Categories, *category)
+		}
+		sort.Slice(discussionList.Categories, func(i, j int) bool {
+			return discussionList.Categories[i].Name < discussionList.Categories[j].Name
+		})
+		discussionLists = append(discussionLists, discussionList)
+	}
+	return discussionLists
+}
+
+// Distinct lists the discussions of the categories once, a question
+// started and answered during the reported days is in both lists
+func Distinct(categories []configs.DiscussionCategory) []configs.Discussion {
+	var distinct []configs.Discussion
+	seen := map[string]bool{}
+	for _, category := range categories {
+		for _, list := range [][]configs.Discussion{category.New, category.Answered} {
+			for _, discussion := range list {
+				if seen[discussion.URL] {
+					continue
+				}
+				seen[discussion.URL] = true
+				distinct = append(distinct, discussion)
+			}
+		}
+	}
+	return distinct
+}
+
+func matchesCategory(category string, categories []string) bool {
+	if len(categories) == 0 {
+		return true
+	}
+	for _, name := range categories {
+		if strings.EqualFold(strings.TrimSpace(name), category) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,163 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"github-updates/internal/pkg/schema"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateLayout is understood by the spreadsheet applications
+const dateLayout = "2006-01-02 15:04:05"
+
+// DefaultColumns are exported when the output lists no columns
+var DefaultColumns = []string{
+	"organization",
+	"repository",
+	"number",
+	"title",
+	"author",
+	"created",
+	"merged",
+	"published",
+	"labels",
+	"state",
+	"url",
+}
+
+// columnValues has the value of each known column for an item
+var columnValues = map[string]func(org string, repo string, item schema.Item) string{
+	"organization": func(org string, _ string, _ schema.Item) string { return org },
+	"repository":   func(_ string, repo string, _ schema.Item) string { return repo },
+	"id": func(_ string, _ string, item schema.Item) string {
+		if item.ID == 0 {
+			return ""
+		}
+		return strconv.FormatInt(item.ID, 10)
+	},
+	"number": func(_ string, _ string, item schema.Item) string {
+		if item.Number == 0 {
+			return ""
+		}
+		return strconv.Itoa(item.Number)
+	},
+	"title": func(_ string, _ string, item schema.Item) string { return item.Title },
+	"author": func(_ string, _ string, item schema.Item) string {
+		// the commits of the authors with no GitHub account
+		if item.Author.Login == "" {
+			return item.Author.Name
+		}
+		return item.Author.Login
+	},
+	"created":   func(_ string, _ string, item schema.Item) string { return formatDate(item.CreatedAt) },
+	"updated":   func(_ string, _ string, item schema.Item) string { return formatDate(item.UpdatedAt) },
+	"merged":    func(_ string, _ string, item schema.Item) string { return formatDate(item.MergedAt) },
+	"closed":    func(_ string, _ string, item schema.Item) string { return formatDate(item.ClosedAt) },
+	"published": func(_ string, _ string, item schema.Item) string { return formatDate(item.PublishedAt) },
+	"labels":    func(_ string, _ string, item schema.Item) string { return strings.Join(item.Labels, "; ") },
+	"state":     func(_ string, _ string, item schema.Item) string { return item.State },
+	"comments":  func(_ string, _ string, item schema.Item) string { return strconv.Itoa(item.Comments) },
+	"tag":       func(_ string, _ string, item schema.Item) string { return item.TagName },
+	"url":       func(_ string, _ string, item schema.Item) string { return item.URL },
+	"sha":       func(_ string, _ string, item schema.Item) string { return item.SHA },
+	"category":  func(_ string, _ string, item schema.Item) string { return item.Category },
+	"answered":  func(_ string, _ string, item schema.Item) string { return formatDate(item.AnsweredAt) },
+	"type":      func(_ string, _ string, item schema.Item) string { return item.Type },
+	"severity":  func(_ string, _ string, item schema.Item) string { return item.Severity },
+	"affected":  func(_ string, _ string, item schema.Item) string { return item.AffectedVersions },
+	"patched":   func(_ string, _ string, item schema.Item) string { return item.PatchedVersions },
+	"references": func(_ string, _ string, item schema.Item) string {
+		var references []string
+		for _, id := range []string{item.GHSAID, item.CVEID} {
+			if id != "" {
+				references = append(references, id)
+			}
+		}
+		return strings.Join(append(references, item.References...), "; ")
+	},
+	"co-authors": func(_ string, _ string, item schema.Item) string {
+		var coAuthors []string
+		for _, coAuthor := range item.CoAuthors {
+			name := coAuthor.Login
+			if name == "" {
+				name = coAuthor.Name
+			}
+			coAuthors = append(coAuthors, name)
+		}
+		return strings.Join(coAuthors, "; ")
+	},
+}
+
+// WriteTable writes one row per item of the document after a header
+// row, comma separated for csv and tab separated for tsv
+func WriteTable(w io.Writer, document schema.Document, columns []string, separator rune) error {
+	if len(columns) == 0 {
+		columns = DefaultColumns
+	}
+	for _, column := range columns {
+		if _, ok := columnValues[column]; !ok {
+			return fmt.Errorf("unknown column %v", column)
+		}
+	}
+	writer := csv.NewWriter(w)
+	writer.Comma = separator
+	err := writer.Write(columns)
+	if err != nil {
+		return err
+	}
+	for _, org := range document.Organizations {
+		for _, repo := range org.Repositories {
+			for _, item := range repo.Items {
+				row := make([]string, len(columns))
+				for index, column := range columns {
+					row[index] = sanitize(columnValues[column](org.Name, repo.Name, item))
+				}
+				err = writer.Write(row)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// sanitize keeps every value on a single line, the runs of white
+// space such as the new lines and the tabs become a single space so
+// that the tsv files can be split on the tabs. Values which a
+// spreadsheet would run as a formula, such as a title starting with
+// "=", are prefixed with a quote.
+func sanitize(value string) string {
+	value = strings.Join(strings.Fields(value), " ")
+	if value != "" && strings.ContainsRune("=+-@", rune(value[0])) {
+		value = "'" + value
+	}
+	return value
+}
+
+func formatDate(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+	return value.UTC().Format(dateLayout)
+}
@@ -0,0 +1,103 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package feeds
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+const atomNamespace = "http://www.w3.org/2005/Atom"
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"feed"`
+	Xmlns   string      `xml:"xmlns,attr"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Link    []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Link       atomLink       `xml:"link"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Author     atomAuthor     `xml:"author"`
+	Categories []atomCategory `xml:"category"`
+	Summary    *atomText      `xml:"summary,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// Atom returns the Atom 1.0 document of the feed
+func Atom(feed Feed) ([]byte, error) {
+	document := atomFeed{
+		Xmlns:   atomNamespace,
+		ID:      feed.ID,
+		Title:   feed.Title,
+		Updated: feed.Updated.Format(time.RFC3339),
+	}
+	if feed.Link != "" {
+		document.Link = []atomLink{{Href: feed.Link, Rel: "alternate"}}
+	}
+	for _, entry := range feed.Entries {
+		element := atomEntry{
+			ID:        guid(entry.ID),
+			Title:     entry.Title,
+			Link:      atomLink{Href: entry.Link, Rel: "alternate"},
+			Published: entry.Published.Format(time.RFC3339),
+			Updated:   entry.Updated.Format(time.RFC3339),
+			Author:    atomAuthor{Name: entry.Author.Name, URI: entry.Author.URI},
+		}
+		for _, category := range entry.Categories {
+			element.Categories = append(element.Categories, atomCategory{Term: category})
+		}
+		if entry.Summary != "" {
+			element.Summary = &atomText{Type: "text", Body: entry.Summary}
+		}
+		document.Entries = append(document.Entries, element)
+	}
+	return marshal(document)
+}
+
+func marshal(document interface{}) ([]byte, error) {
+	contents, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), contents...), nil
+}
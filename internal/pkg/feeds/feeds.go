@@ -0,0 +1,262 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package feeds
+
+import (
+	"fmt"
+	"github-updates/internal/pkg/configs"
+	"github-updates/internal/pkg/discussions"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/go-github/v33/github"
+)
+
+// summaryLength is the maximum number of characters taken from
+// the body of an item into the feed entry summary
+const summaryLength = 500
+
+// Feed is the format independent content of a feed
+type Feed struct {
+	ID      string
+	Title   string
+	Link    string
+	Updated time.Time
+	Entries []Entry
+}
+
+// Entry is a single PR, release or issue in the feed
+type Entry struct {
+	// ID is the GitHub node ID, it stays the same across the runs
+	ID         string
+	Title      string
+	Link       string
+	Summary    string
+	Author     Author
+	Published  time.Time
+	Updated    time.Time
+	Categories []string
+}
+
+// Author of the feed entry
+type Author struct {
+	Name   string
+	URI    string
+	Avatar string
+}
+
+// NewFeed builds the feed from the report data, entries are
+// sorted with the most recently updated first
+func NewFeed(title string, link string, v interface{}) (Feed, error) {
+	entries, err := Entries(v)
+	if err != nil {
+		return Feed{}, err
+	}
+	sort.SliceStable(entries, func(first, second int) bool {
+		return entries[first].Updated.After(entries[second].Updated)
+	})
+	feed := Feed{
+		ID:      link,
+		Title:   title,
+		Link:    link,
+		Updated: time.Now().UTC(),
+		Entries: entries,
+	}
+	if len(entries) != 0 {
+		feed.Updated = entries[0].Updated
+	}
+	return feed, nil
+}
+
+// Entries converts the report data into feed entries. It accepts
+// the organization wise lists as well as the per repository
+// details used by the external templates.
+func Entries(v interface{}) ([]Entry, error) {
+	var entries []Entry
+	switch details := v.(type) {
+	case []configs.PullRequestDetails:
+		for _, org := range details {
+			for _, repo := range org.PrRepoLists {
+				entries = append(entries, pullRequestEntries(org.Organization, repo.Repository, repo.PRs)...)
+			}
+		}
+	case []configs.ReleaseDetails:
+		for _, org := range details {
+			for _, repo := range org.ReleaseRepoLists {
+				entries = append(entries, releaseEntries(org.Organization, repo.Repository, repo.Releases)...)
+			}
+		}
+	case []configs.IssueDetails:
+		for _, org := range details {
+			for _, repo := range org.IssueLists {
+				entries = append(entries, issueEntries(org.Organization, repo.Repository, repo.Issues)...)
+			}
+		}
+	case configs.ExternalPRDetails:
+		entries = pullRequestEntries(details.Organization.Github, details.Repository.Name, details.PRs)
+	case configs.ExternalReleaseDetails:
+		entries = releaseEntries(details.Organization.Github, details.Repository.Name, details.Releases)
+	case configs.ExternalIssueDetails:
+		entries = issueEntries(details.Organization.Github, details.Repository.Name, details.Issues)
+	case configs.ExternalDiscussionDetails:
+		entries = discussionEntries(details.Organization.Github, details.Repository.Name, details.Categories)
+	default:
+		return nil, fmt.Errorf("feeds are not supported for %T", v)
+	}
+	return entries, nil
+}
+
+func pullRequestEntries(org string, repo string, prs []github.PullRequest) []Entry {
+	var entries []Entry
+	for _, pr := range prs {
+		entries = append(entries, Entry{
+			ID:         pr.GetNodeID(),
+			Title:      fmt.Sprintf("%v/%v#%v: %v", org, repo, pr.GetNumber(), pr.GetTitle()),
+			Link:       pr.GetHTMLURL(),
+			Summary:    summary(pr.GetBody()),
+			Author:     userAuthor(pr.GetUser()),
+			Published:  pr.GetCreatedAt().UTC(),
+			Updated:    pr.GetUpdatedAt().UTC(),
+			Categories: categories(org, repo),
+		})
+	}
+	return entries
+}
+
+func releaseEntries(org string, repo string, releases []github.RepositoryRelease) []Entry {
+	var entries []Entry
+	for _, release := range releases {
+		name := release.GetName()
+		if name == "" {
+			name = release.GetTagName()
+		}
+		// releases have no update time, published is the latest change
+		published := release.GetPublishedAt().UTC()
+		entries = append(entries, Entry{
+			ID:         release.GetNodeID(),
+			Title:      fmt.Sprintf("%v/%v %v", org, repo, name),
+			Link:       release.GetHTMLURL(),
+			Summary:    summary(release.GetBody()),
+			Author:     userAuthor(release.GetAuthor()),
+			Published:  published,
+			Updated:    published,
+			Categories: categories(org, repo),
+		})
+	}
+	return entries
+}
+
+func issueEntries(org string, repo string, issues []github.Issue) []Entry {
+	var entries []Entry
+	for _, issue := range issues {
+		entryCategories := categories(org, repo)
+		for _, label := range issue.Labels {
+			entryCategories = append(entryCategories, label.GetName())
+		}
+		entries = append(entries, Entry{
+			ID:         issue.GetNodeID(),
+			Title:      fmt.Sprintf("%v/%v#%v: %v", org, repo, issue.GetNumber(), issue.GetTitle()),
+			Link:       issue.GetHTMLURL(),
+			Summary:    summary(issue.GetBody()),
+			Author:     userAuthor(issue.GetUser()),
+			Published:  issue.GetCreatedAt().UTC(),
+			Updated:    issue.GetUpdatedAt().UTC(),
+			Categories: entryCategories,
+		})
+	}
+	return entries
+}
+
+func discussionEntries(org string, repo string, discussionCategories []configs.DiscussionCategory) []Entry {
+	var entries []Entry
+	for _, discussion := range discussions.Distinct(discussionCategories) {
+		entries = append(entries, Entry{
+			// the discussions are read without their node ID
+			ID:    discussion.URL,
+			Title: fmt.Sprintf("%v/%v#%v: %v", org, repo, discussion.Number, discussion.Title),
+			Link:  discussion.URL,
+			Author: Author{
+				Name:   discussion.Author,
+				URI:    discussion.AuthorURL,
+				Avatar: discussion.AuthorAvatarURL,
+			},
+			Published:  discussion.CreatedAt.UTC(),
+			Updated:    discussion.UpdatedAt.UTC(),
+			Categories: append(categories(org, repo), discussion.Category),
+		})
+	}
+	return entries
+}
+
+// categories has the organization and the repository of the entry
+func categories(org string, repo string) []string {
+	return []string{org, org + "/" + repo}
+}
+
+func userAuthor(user *github.User) Author {
+	return Author{
+		Name:   user.GetLogin(),
+		URI:    user.GetHTMLURL(),
+		Avatar: user.GetAvatarURL(),
+	}
+}
+
+// summary shortens the body to summaryLength characters
+func summary(body string) string {
+	body = strings.TrimSpace(body)
+	if utf8.RuneCountInString(body) <= summaryLength {
+		return body
+	}
+	return string([]rune(body)[:summaryLength]) + "…"
+}
+
+// guid is the globally unique entry id built from the node ID
+func guid(nodeID string) string {
+	return "tag:github.com,2008:" + nodeID
+}
+
+// Marshal returns the feed document in the given format
+func Marshal(format string, feed Feed) ([]byte, error) {
+	switch format {
+	case configs.FormatAtom:
+		return Atom(feed)
+	case configs.FormatRSS:
+		return RSS(feed)
+	case configs.FormatJSONFeed:
+		return JSONFeed(feed)
+	}
+	return nil, fmt.Errorf("unknown feed format %v", format)
+}
+
+// Extension is the file name ending of a feed written next to a
+// generated file, "atom.xml" and "rss.xml" for the XML feeds and
+// "feed.json" for the JSON Feed
+func Extension(format string) string {
+	if format == configs.FormatJSONFeed {
+		return "feed.json"
+	}
+	return format + ".xml"
+}
+
+// IsFeed tells if the output format is one of the feeds
+func IsFeed(format string) bool {
+	return format == configs.FormatAtom ||
+		format == configs.FormatRSS ||
+		format == configs.FormatJSONFeed
+}
@@ -0,0 +1,75 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package feeds
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const jsonFeedVersion = "https://jsonfeed.org/version/1.1"
+
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url,omitempty"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            string           `json:"id"`
+	URL           string           `json:"url,omitempty"`
+	Title         string           `json:"title,omitempty"`
+	ContentText   string           `json:"content_text"`
+	DatePublished string           `json:"date_published,omitempty"`
+	DateModified  string           `json:"date_modified,omitempty"`
+	Authors       []jsonFeedAuthor `json:"authors,omitempty"`
+	Tags          []string         `json:"tags,omitempty"`
+}
+
+type jsonFeedAuthor struct {
+	Name   string `json:"name,omitempty"`
+	URL    string `json:"url,omitempty"`
+	Avatar string `json:"avatar,omitempty"`
+}
+
+// JSONFeed returns the JSON Feed 1.1 document of the feed
+func JSONFeed(feed Feed) ([]byte, error) {
+	document := jsonFeed{
+		Version:     jsonFeedVersion,
+		Title:       feed.Title,
+		HomePageURL: feed.Link,
+		Items:       []jsonFeedItem{},
+	}
+	for _, entry := range feed.Entries {
+		document.Items = append(document.Items, jsonFeedItem{
+			ID:            guid(entry.ID),
+			URL:           entry.Link,
+			Title:         entry.Title,
+			ContentText:   entry.Summary,
+			DatePublished: entry.Published.Format(time.RFC3339),
+			DateModified:  entry.Updated.Format(time.RFC3339),
+			Authors: []jsonFeedAuthor{{
+				Name:   entry.Author.Name,
+				URL:    entry.Author.URI,
+				Avatar: entry.Author.Avatar,
+			}},
+			Tags: entry.Categories,
+		})
+	}
+	return json.MarshalIndent(document, "", "  ")
+}
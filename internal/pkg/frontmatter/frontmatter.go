@@ -0,0 +1,182 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package frontmatter emits the front matter of the files generated
+// with the external templates, for the static site generators
+package frontmatter
+
+import (
+	"fmt"
+	"github-updates/internal/pkg/configs"
+	"github-updates/internal/pkg/discussions"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+var slugInvalid = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Fields are written in the front matter, in this order
+type Fields struct {
+	Title        string
+	Date         time.Time
+	Slug         string
+	Organization string
+	Repository   string
+	Kind         string
+	Tags         []string
+	Count        int
+}
+
+// New collects the fields of the per repository details, the tags
+// are the labels of the items
+func New(value interface{}, date time.Time) (Fields, error) {
+	var fields Fields
+	labels := map[string]bool{}
+	switch details := value.(type) {
+	case configs.ExternalPRDetails:
+		fields = newFields(details.Organization, details.Repository, configs.PullRequestReport, len(details.PRs))
+		for _, pr := range details.PRs {
+			for _, label := range pr.Labels {
+				labels[label.GetName()] = true
+			}
+		}
+	case configs.ExternalIssueDetails:
+		fields = newFields(details.Organization, details.Repository, configs.IssueReport, len(details.Issues))
+		for _, issue := range details.Issues {
+			for _, label := range issue.Labels {
+				labels[label.GetName()] = true
+			}
+		}
+	case configs.ExternalReleaseDetails:
+		fields = newFields(details.Organization, details.Repository, configs.ReleaseReport, len(details.Releases))
+	case configs.ExternalDiscussionDetails:
+		fields = newFields(details.Organization, details.Repository, configs.DiscussionReport,
+			len(discussions.Distinct(details.Categories)))
+		for _, category := range details.Categories {
+			labels[category.Name] = true
+		}
+	default:
+		return Fields{}, fmt.Errorf("no front matter for %T", value)
+	}
+	fields.Date = date
+	fields.Tags = []string{}
+	for label := range labels {
+		fields.Tags = append(fields.Tags, label)
+	}
+	sort.Strings(fields.Tags)
+	return fields, nil
+}
+
+func newFields(
+	org configs.OrganizationStructure,
+	repo configs.RepositoryStructure,
+	kind string,
+	count int,
+) Fields {
+	organization := org.Name
+	if organization == "" {
+		organization = org.Github
+	}
+	return Fields{
+		Title:        fmt.Sprintf("%v %v: %v", organization, repo.Name, strings.ReplaceAll(kind, "-", " ")),
+		Slug:         Slug(repo.Name + "-" + kind),
+		Organization: org.Github,
+		Repository:   repo.Name,
+		Kind:         kind,
+		Count:        count,
+	}
+}
+
+// Slug makes the text safe for the urls and the file names
+func Slug(text string) string {
+	return strings.Trim(slugInvalid.ReplaceAllString(strings.ToLower(text), "-"), "-")
+}
+
+// Marshal writes the front matter block in the format, including the
+// delimiters and a trailing new line
+func Marshal(format string, fields Fields) (string, error) {
+	switch format {
+	case configs.FrontMatterYAML:
+		// the date is written in RFC 3339, which both Hugo and Jekyll parse
+		contents, err := yaml.Marshal(yaml.MapSlice{
+			{Key: "title", Value: fields.Title},
+			{Key: "date", Value: fields.Date.Format(time.RFC3339)},
+			{Key: "slug", Value: fields.Slug},
+			{Key: "organization", Value: fields.Organization},
+			{Key: "repository", Value: fields.Repository},
+			{Key: "kind", Value: fields.Kind},
+			{Key: "tags", Value: fields.Tags},
+			{Key: "count", Value: fields.Count},
+		})
+		if err != nil {
+			return "", err
+		}
+		return "---\n" + string(contents) + "---\n", nil
+	case configs.FrontMatterTOML:
+		var builder strings.Builder
+		builder.WriteString("+++\n")
+		fmt.Fprintf(&builder, "title = %v\n", tomlString(fields.Title))
+		fmt.Fprintf(&builder, "date = %v\n", fields.Date.Format(time.RFC3339))
+		fmt.Fprintf(&builder, "slug = %v\n", tomlString(fields.Slug))
+		fmt.Fprintf(&builder, "organization = %v\n", tomlString(fields.Organization))
+		fmt.Fprintf(&builder, "repository = %v\n", tomlString(fields.Repository))
+		fmt.Fprintf(&builder, "kind = %v\n", tomlString(fields.Kind))
+		tags := make([]string, len(fields.Tags))
+		for index, tag := range fields.Tags {
+			tags[index] = tomlString(tag)
+		}
+		fmt.Fprintf(&builder, "tags = [%v]\n", strings.Join(tags, ", "))
+		fmt.Fprintf(&builder, "count = %v\n", fields.Count)
+		builder.WriteString("+++\n")
+		return builder.String(), nil
+	}
+	return "", fmt.Errorf("unknown front matter format %v", format)
+}
+
+// tomlString quotes the text as a TOML basic string
+func tomlString(text string) string {
+	var builder strings.Builder
+	builder.WriteByte('"')
+	for _, character := range text {
+		switch {
+		case character == '"' || character == '\\':
+			builder.WriteByte('\\')
+			builder.WriteRune(character)
+		case character < 0x20 || character == 0x7f:
+			fmt.Fprintf(&builder, "\\u%04X", character)
+		default:
+			builder.WriteRune(character)
+		}
+	}
+	builder.WriteByte('"')
+	return builder.String()
+}
+
+// FileName is the name of the generated file for the convention,
+// Jekyll posts start with the date
+func FileName(convention string, fields Fields, repository string, extension string) string {
+	switch convention {
+	case configs.ContentConventionHugo:
+		return fields.Slug + extension
+	case configs.ContentConventionJekyll:
+		return fields.Date.Format("2006-01-02") + "-" + fields.Slug + extension
+	}
+	return repository + extension
+}
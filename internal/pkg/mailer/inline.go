@@ -0,0 +1,431 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mailer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// cssComment matches the comments of the stylesheet
+var cssComment = regexp.MustCompile(`(?s)/\*.*?\*/`)
+
+// rule is a single selector with its declarations
+type rule struct {
+	selector     []compound
+	declarations string
+	specificity  int
+	order        int
+}
+
+// compound is a part of a selector such as "div.thumbnail", with the
+// combinator joining it to the part before
+type compound struct {
+	tag     string
+	id      string
+	classes []string
+	// child is set for the ">" combinator, else it is a descendant
+	child bool
+}
+
+// Document is a rendered html report. The stylesheets it links and
+// the images it shows are read relative to Dir, the folder of the
+// summary file.
+type Document struct {
+	HTML string
+	Dir  string
+}
+
+// EmailHTML merges the bodies of the rendered html documents into
+// a single document and inlines the stylesheets, as email clients
+// strip the linked and embedded styles. Every document gets the
+// rules of the stylesheets it links, of its style blocks and then
+// of the given stylesheets. The local images are returned to be
+// attached to the message, their sources refer to them by content
+// id.
+func EmailHTML(documents []Document, stylesheets []string) (string, []Inline, error) {
+	merged, err := html.Parse(strings.NewReader(
+		"<!DOCTYPE html><html><head><meta charset=\"utf-8\"></head><body></body></html>",
+	))
+	if err != nil {
+		return "", nil, err
+	}
+	body := findElement(merged, atom.Body)
+	images := &inlineImages{contentIDs: map[string]string{}}
+	for _, document := range documents {
+		node, err := html.Parse(strings.NewReader(document.HTML))
+		if err != nil {
+			return "", nil, err
+		}
+		documentBody := findElement(node, atom.Body)
+		if documentBody == nil {
+			return "", nil, errors.New("rendered document has no body")
+		}
+		sheets, err := documentStylesheets(node, document.Dir)
+		if err != nil {
+			return "", nil, err
+		}
+		removeElements(node, atom.Link, atom.Script, atom.Style)
+
+		var rules []rule
+		for _, stylesheet := range append(sheets, stylesheets...) {
+			rules = append(rules, parseRules(stylesheet, len(rules))...)
+		}
+		sort.SliceStable(rules, func(first, second int) bool {
+			if rules[first].specificity != rules[second].specificity {
+				return rules[first].specificity < rules[second].specificity
+			}
+			return rules[first].order < rules[second].order
+		})
+		applyRules(node, rules)
+		err = images.embed(documentBody, document.Dir)
+		if err != nil {
+			return "", nil, err
+		}
+
+		// the style of the body is kept on a block of its own
+		section := &html.Node{Type: html.ElementNode, DataAtom: atom.Div, Data: "div"}
+		if style := attribute(documentBody, "style"); style != "" {
+			section.Attr = []html.Attribute{{Key: "style", Val: style}}
+		}
+		for child := documentBody.FirstChild; child != nil; child = documentBody.FirstChild {
+			documentBody.RemoveChild(child)
+			section.AppendChild(child)
+		}
+		body.AppendChild(section)
+	}
+
+	var buffer bytes.Buffer
+	err = html.Render(&buffer, merged)
+	return buffer.String(), images.files, err
+}
+
+// documentStylesheets reads the local stylesheets linked by the
+// document and its style blocks, in the order they appear
+func documentStylesheets(node *html.Node, dir string) ([]string, error) {
+	var stylesheets []string
+	if node.Type == html.ElementNode {
+		switch node.DataAtom {
+		case atom.Link:
+			path, local := localPath(attribute(node, "href"), dir)
+			if local && hasToken(attribute(node, "rel"), "stylesheet") {
+				contents, err := ioutil.ReadFile(path)
+				if err != nil {
+					return nil, fmt.Errorf("reading the linked stylesheet: %v", err)
+				}
+				stylesheets = append(stylesheets, string(contents))
+			}
+		case atom.Style:
+			for child := node.FirstChild; child != nil; child = child.NextSibling {
+				if child.Type == html.TextNode {
+					stylesheets = append(stylesheets, child.Data)
+				}
+			}
+		}
+	}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		found, err := documentStylesheets(child, dir)
+		if err != nil {
+			return nil, err
+		}
+		stylesheets = append(stylesheets, found...)
+	}
+	return stylesheets, nil
+}
+
+// inlineImages has the local images of the email, an image shown
+// several times is attached once
+type inlineImages struct {
+	files      []Inline
+	contentIDs map[string]string
+}
+
+// embed attaches the local images of the element and its children
+// and points their sources to the attachments
+func (images *inlineImages) embed(node *html.Node, dir string) error {
+	if node.Type == html.ElementNode && node.DataAtom == atom.Img {
+		for index, attr := range node.Attr {
+			if attr.Key != "src" {
+				continue
+			}
+			path, local := localPath(attr.Val, dir)
+			if !local {
+				break
+			}
+			contentID, found := images.contentIDs[path]
+			if !found {
+				contents, err := ioutil.ReadFile(path)
+				if err != nil {
+					return fmt.Errorf("reading the image: %v", err)
+				}
+				contentType := mime.TypeByExtension(filepath.Ext(path))
+				if contentType == "" {
+					contentType = http.DetectContentType(contents)
+				}
+				contentID = fmt.Sprintf("image%v.%v@github-updates", len(images.files)+1, filepath.Base(path))
+				images.contentIDs[path] = contentID
+				images.files = append(images.files, Inline{
+					ContentID:   contentID,
+					ContentType: contentType,
+					Data:        contents,
+				})
+			}
+			node.Attr[index].Val = "cid:" + contentID
+			break
+		}
+	}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		err := images.embed(child, dir)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// localPath resolves a relative reference such as "../css/main.css"
+// against the folder, the references with a scheme or a host such as
+// the avatars are not local
+func localPath(reference string, dir string) (string, bool) {
+	if reference == "" {
+		return "", false
+	}
+	parsed, err := url.Parse(reference)
+	if err != nil || parsed.Scheme != "" || parsed.Host != "" || parsed.Path == "" {
+		return "", false
+	}
+	path := filepath.FromSlash(parsed.Path)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	return path, true
+}
+
+func hasToken(value string, token string) bool {
+	for _, field := range strings.Fields(strings.ToLower(value)) {
+		if field == token {
+			return true
+		}
+	}
+	return false
+}
+
+// parseRules reads the rules with simple selectors. The pseudo
+// classes, attribute selectors and the at-rules can not be inlined
+// and are skipped.
+func parseRules(stylesheet string, order int) []rule {
+	var rules []rule
+	stylesheet = cssComment.ReplaceAllString(stylesheet, "")
+	for _, block := range strings.Split(stylesheet, "}") {
+		parts := strings.SplitN(block, "{", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		declarations := strings.Join(strings.Fields(parts[1]), " ")
+		if declarations == "" || strings.Contains(parts[0], "@") {
+			continue
+		}
+		for _, selector := range strings.Split(parts[0], ",") {
+			compounds, specificity, ok := parseSelector(selector)
+			if !ok {
+				continue
+			}
+			rules = append(rules, rule{
+				selector:     compounds,
+				declarations: strings.TrimSuffix(declarations, ";"),
+				specificity:  specificity,
+				order:        order,
+			})
+			order++
+		}
+	}
+	return rules
+}
+
+func parseSelector(selector string) ([]compound, int, bool) {
+	if strings.ContainsAny(selector, ":[+~*") {
+		return nil, 0, false
+	}
+	selector = strings.ReplaceAll(selector, ">", " > ")
+	var compounds []compound
+	specificity := 0
+	child := false
+	for _, token := range strings.Fields(selector) {
+		if token == ">" {
+			child = true
+			continue
+		}
+		part := compound{child: child}
+		child = false
+		for _, piece := range splitCompound(token) {
+			switch piece[0] {
+			case '#':
+				part.id = piece[1:]
+				specificity += 100
+			case '.':
+				part.classes = append(part.classes, piece[1:])
+				specificity += 10
+			default:
+				part.tag = strings.ToLower(piece)
+				specificity++
+			}
+		}
+		compounds = append(compounds, part)
+	}
+	return compounds, specificity, len(compounds) != 0
+}
+
+// splitCompound splits "div.a.b#c" into "div", ".a", ".b" and "#c"
+func splitCompound(token string) []string {
+	var pieces []string
+	start := 0
+	for index := 1; index < len(token); index++ {
+		if token[index] == '.' || token[index] == '#' {
+			pieces = append(pieces, token[start:index])
+			start = index
+		}
+	}
+	return append(pieces, token[start:])
+}
+
+// applyRules sets the style attribute of every element, the
+// declarations already in the attribute take precedence
+func applyRules(node *html.Node, rules []rule) {
+	if node.Type == html.ElementNode {
+		var styles []string
+		for _, r := range rules {
+			if matches(node, r.selector) {
+				styles = append(styles, r.declarations)
+			}
+		}
+		if len(styles) != 0 {
+			setStyle(node, strings.Join(styles, "; "))
+		}
+	}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		applyRules(child, rules)
+	}
+}
+
+func setStyle(node *html.Node, style string) {
+	for index, attribute := range node.Attr {
+		if attribute.Key == "style" {
+			node.Attr[index].Val = style + "; " + attribute.Val
+			return
+		}
+	}
+	node.Attr = append(node.Attr, html.Attribute{Key: "style", Val: style})
+}
+
+// matches checks the selector right to left against the element
+// and its ancestors
+func matches(node *html.Node, selector []compound) bool {
+	last := len(selector) - 1
+	if !matchesCompound(node, selector[last]) {
+		return false
+	}
+	if last == 0 {
+		return true
+	}
+	rest := selector[:last]
+	if selector[last].child {
+		parent := node.Parent
+		return parent != nil && parent.Type == html.ElementNode && matches(parent, rest)
+	}
+	for ancestor := node.Parent; ancestor != nil; ancestor = ancestor.Parent {
+		if ancestor.Type == html.ElementNode && matches(ancestor, rest) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesCompound(node *html.Node, part compound) bool {
+	if part.tag != "" && part.tag != node.Data {
+		return false
+	}
+	if part.id != "" && attribute(node, "id") != part.id {
+		return false
+	}
+	classes := strings.Fields(attribute(node, "class"))
+	for _, class := range part.classes {
+		found := false
+		for _, value := range classes {
+			if value == class {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func attribute(node *html.Node, key string) string {
+	for _, attr := range node.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+func findElement(node *html.Node, element atom.Atom) *html.Node {
+	if node.Type == html.ElementNode && node.DataAtom == element {
+		return node
+	}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if found := findElement(child, element); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func removeElements(node *html.Node, elements ...atom.Atom) {
+	for child := node.FirstChild; child != nil; {
+		next := child.NextSibling
+		removed := false
+		if child.Type == html.ElementNode {
+			for _, element := range elements {
+				if child.DataAtom == element {
+					node.RemoveChild(child)
+					removed = true
+					break
+				}
+			}
+		}
+		if !removed {
+			removeElements(child, elements...)
+		}
+		child = next
+	}
+}
@@ -0,0 +1,75 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mailer
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEmailHTMLStylesheets(t *testing.T) {
+	dir := t.TempDir()
+	err := ioutil.WriteFile(filepath.Join(dir, "issues.css"), []byte("li { font-weight: bold; }"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issues := `<html><head>
+<link rel="stylesheet" href="issues.css">
+<link rel="stylesheet" href="https://cdn.example.org/remote.css">
+</head><body><ul><li>issue</li></ul></body></html>`
+	milestones := `<html><head><style>
+body { margin: 0; }
+.bar { width: 10px; }
+</style></head><body><span class="bar">milestone</span></body></html>`
+
+	body, images, err := EmailHTML([]Document{
+		{HTML: issues, Dir: dir},
+		{HTML: milestones, Dir: dir},
+	}, []string{".bar { color: blue; }"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(images) != 0 {
+		t.Errorf("got %v images, want none", len(images))
+	}
+	for _, want := range []string{
+		`<li style="font-weight: bold">issue</li>`,
+		`<div style="margin: 0">`,
+		`<span class="bar" style="width: 10px; color: blue">milestone</span>`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("%v is not in %v", want, body)
+		}
+	}
+	// the rules of a report do not leak into the others
+	if strings.Count(body, "font-weight") != 1 {
+		t.Errorf("the issue stylesheet is applied to the other reports: %v", body)
+	}
+	if strings.Contains(body, "<style") || strings.Contains(body, "<link") {
+		t.Errorf("the styles are left in the body: %v", body)
+	}
+}
+
+func TestEmailHTMLMissingStylesheet(t *testing.T) {
+	document := `<html><head><link rel="stylesheet" href="missing.css"></head><body></body></html>`
+	_, _, err := EmailHTML([]Document{{HTML: document, Dir: t.TempDir()}}, nil)
+	if err == nil {
+		t.Error("a missing linked stylesheet is not reported")
+	}
+}
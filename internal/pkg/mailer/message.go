@@ -0,0 +1,172 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mailer
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// Message is a newsletter with the html body and the plain text
+// alternative. Inline has the images the html body shows by their
+// content id.
+type Message struct {
+	From    string
+	To      []string
+	Subject string
+	HTML    string
+	Text    string
+	Inline  []Inline
+}
+
+// Inline is a file attached to be shown in the html body, such as
+// an image with a "cid:" source
+type Inline struct {
+	ContentID   string
+	ContentType string
+	Data        []byte
+}
+
+// Bytes returns the message in the MIME multipart/alternative
+// format, the plain text comes first as the least preferred part.
+// The html part is a multipart/related with the inline files when
+// there are some.
+func (m Message) Bytes() ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := multipart.NewWriter(&buffer)
+
+	headers := []string{
+		"From: " + m.From,
+		"To: " + strings.Join(m.To, ", "),
+		"Subject: " + mime.QEncoding.Encode("utf-8", m.Subject),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"Message-ID: " + messageID(m.From),
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/alternative; boundary=" + writer.Boundary(),
+	}
+	var message bytes.Buffer
+	message.WriteString(strings.Join(headers, "\r\n") + "\r\n\r\n")
+
+	err := writeText(writer, "text/plain; charset=utf-8", m.Text)
+	if err != nil {
+		return nil, err
+	}
+	if len(m.Inline) == 0 {
+		err = writeText(writer, "text/html; charset=utf-8", m.HTML)
+	} else {
+		err = m.writeRelated(writer)
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = writer.Close()
+	if err != nil {
+		return nil, err
+	}
+	message.Write(buffer.Bytes())
+	return message.Bytes(), nil
+}
+
+// writeRelated adds the html part with the inline files
+func (m Message) writeRelated(writer *multipart.Writer) error {
+	var buffer bytes.Buffer
+	related := multipart.NewWriter(&buffer)
+	err := writeText(related, "text/html; charset=utf-8", m.HTML)
+	if err != nil {
+		return err
+	}
+	for _, file := range m.Inline {
+		partWriter, err := related.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {file.ContentType},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-ID":                {"<" + file.ContentID + ">"},
+			"Content-Disposition":       {"inline"},
+		})
+		if err != nil {
+			return err
+		}
+		err = writeBase64(partWriter, file.Data)
+		if err != nil {
+			return err
+		}
+	}
+	err = related.Close()
+	if err != nil {
+		return err
+	}
+	partWriter, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {"multipart/related; boundary=" + related.Boundary()},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = partWriter.Write(buffer.Bytes())
+	return err
+}
+
+// writeText adds a quoted-printable text part
+func writeText(writer *multipart.Writer, contentType string, body string) error {
+	partWriter, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+	encoder := quotedprintable.NewWriter(partWriter)
+	_, err = encoder.Write([]byte(body))
+	if err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// writeBase64 encodes the data in lines of 76 characters as
+// required for the mail bodies
+func writeBase64(w io.Writer, data []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 76 {
+		_, err := io.WriteString(w, encoded[:76]+"\r\n")
+		if err != nil {
+			return err
+		}
+		encoded = encoded[76:]
+	}
+	_, err := io.WriteString(w, encoded+"\r\n")
+	return err
+}
+
+// messageID builds a unique message id in the domain of the sender
+func messageID(from string) string {
+	random := make([]byte, 12)
+	_, _ = rand.Read(random)
+	domain := "localhost"
+	if at := strings.LastIndex(from, "@"); at != -1 {
+		domain = strings.Trim(from[at+1:], "> ")
+	}
+	return fmt.Sprintf("<%v.%v@%v>", time.Now().Unix(), hex.EncodeToString(random), domain)
+}
@@ -0,0 +1,98 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mailer
+
+import (
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+)
+
+// Server has the SMTP connection settings
+type Server struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	// StartTLS upgrades the connection, it is required when the
+	// server is not on the local machine and auth is used
+	StartTLS bool
+}
+
+// Send delivers the message to every recipient
+func Send(server Server, m Message) error {
+	if len(m.To) == 0 {
+		return errors.New("no recipients to send the newsletter to")
+	}
+	from, err := mail.ParseAddress(m.From)
+	if err != nil {
+		return err
+	}
+	contents, err := m.Bytes()
+	if err != nil {
+		return err
+	}
+
+	client, err := smtp.Dial(net.JoinHostPort(server.Host, strconv.Itoa(server.Port)))
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if server.StartTLS {
+		err = client.StartTLS(&tls.Config{ServerName: server.Host})
+		if err != nil {
+			return err
+		}
+	}
+	if server.Username != "" {
+		err = client.Auth(smtp.PlainAuth("", server.Username, server.Password, server.Host))
+		if err != nil {
+			return err
+		}
+	}
+	err = client.Mail(from.Address)
+	if err != nil {
+		return err
+	}
+	for _, recipient := range m.To {
+		address, err := mail.ParseAddress(recipient)
+		if err != nil {
+			return err
+		}
+		err = client.Rcpt(address.Address)
+		if err != nil {
+			return err
+		}
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(contents)
+	if err != nil {
+		return err
+	}
+	err = writer.Close()
+	if err != nil {
+		return err
+	}
+	return client.Quit()
+}
@@ -0,0 +1,211 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mailer
+
+import (
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// smtpStub accepts a single SMTP session and hands over the data of
+// the message, it offers no extension so that no TLS or auth is used
+func smtpStub(t *testing.T) (Server, <-chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		text := textproto.NewConn(conn)
+		_ = text.PrintfLine("220 stub ready")
+		for {
+			line, err := text.ReadLine()
+			if err != nil {
+				return
+			}
+			command := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+			switch command {
+			case "EHLO", "HELO", "MAIL", "RCPT", "RSET", "NOOP":
+				_ = text.PrintfLine("250 ok")
+			case "DATA":
+				_ = text.PrintfLine("354 go ahead")
+				data, err := ioutil.ReadAll(text.DotReader())
+				if err != nil {
+					return
+				}
+				received <- string(data)
+				_ = text.PrintfLine("250 queued")
+			case "QUIT":
+				_ = text.PrintfLine("221 bye")
+				return
+			default:
+				_ = text.PrintfLine("502 not implemented")
+			}
+		}
+	}()
+	host, port, err := net.SplitHostPort(listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	portNumber, err := strconv.Atoi(port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Server{Host: host, Port: portNumber}, received
+}
+
+func TestSendNewsletter(t *testing.T) {
+	dir := t.TempDir()
+	err := os.MkdirAll(filepath.Join(dir, "css"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(filepath.Join(dir, "css", "main.css"), []byte(".org { color: red; }"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.MkdirAll(filepath.Join(dir, "generated"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chart := []byte(`<svg xmlns="http://www.w3.org/2000/svg"></svg>`)
+	err = ioutil.WriteFile(filepath.Join(dir, "generated", "chart.svg"), chart, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	document := `<html><head><link rel="stylesheet" href="../css/main.css"></head>
+<body><ol class="org"><li>hyperledger</li></ol><img src="chart.svg" alt="chart"></body></html>`
+	body, images, err := EmailHTML([]Document{{HTML: document, Dir: filepath.Join(dir, "generated")}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server, received := smtpStub(t)
+	err = Send(server, Message{
+		From:    "Updates <updates@example.org>",
+		To:      []string{"newsletter@example.org"},
+		Subject: "Weekly updates",
+		HTML:    body,
+		Text:    "# Weekly updates",
+		Inline:  images,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	message, err := mail.ReadMessage(strings.NewReader(<-received))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject := message.Header.Get("Subject"); subject != "Weekly updates" {
+		t.Errorf("subject is %q", subject)
+	}
+	parts := readParts(t, message.Header.Get("Content-Type"), message.Body, "multipart/alternative")
+	if len(parts) != 2 {
+		t.Fatalf("got %v alternative parts, want 2", len(parts))
+	}
+	if parts[0].contentType != "text/plain" || !strings.Contains(parts[0].body, "# Weekly updates") {
+		t.Errorf("first part is %v: %q", parts[0].contentType, parts[0].body)
+	}
+	related := readParts(t, parts[1].header.Get("Content-Type"), strings.NewReader(parts[1].body), "multipart/related")
+	if len(related) != 2 {
+		t.Fatalf("got %v related parts, want 2", len(related))
+	}
+	html := related[0]
+	if html.contentType != "text/html" {
+		t.Fatalf("related part is %v, want text/html", html.contentType)
+	}
+	if !strings.Contains(html.body, `<ol class="org" style="color: red">`) {
+		t.Errorf("the linked stylesheet is not inlined: %v", html.body)
+	}
+	if strings.Contains(html.body, "<link") {
+		t.Errorf("the link is left in the body: %v", html.body)
+	}
+	image := related[1]
+	contentID := strings.Trim(image.header.Get("Content-Id"), "<>")
+	if image.contentType != "image/svg+xml" || contentID == "" {
+		t.Fatalf("image part is %v with content id %q", image.contentType, contentID)
+	}
+	if !strings.Contains(html.body, `src="cid:`+contentID+`"`) {
+		t.Errorf("the image does not refer to %v: %v", contentID, html.body)
+	}
+	if image.body != string(chart) {
+		t.Errorf("image is %q", image.body)
+	}
+}
+
+// part is a decoded body part of the message
+type part struct {
+	header      textproto.MIMEHeader
+	contentType string
+	body        string
+}
+
+// readParts reads the parts of the multipart body, the quoted
+// printable ones are decoded by the multipart reader and the base64
+// ones here
+func readParts(t *testing.T, contentType string, body io.Reader, want string) []part {
+	t.Helper()
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mediaType != want {
+		t.Fatalf("content type is %v, want %v", mediaType, want)
+	}
+	var parts []part
+	reader := multipart.NewReader(body, params["boundary"])
+	for {
+		next, err := reader.NextPart()
+		if err == io.EOF {
+			return parts
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		var contents io.Reader = next
+		if next.Header.Get("Content-Transfer-Encoding") == "base64" {
+			contents = base64.NewDecoder(base64.StdEncoding, next)
+		}
+		data, err := ioutil.ReadAll(contents)
+		if err != nil {
+			t.Fatal(err)
+		}
+		partType, _, err := mime.ParseMediaType(next.Header.Get("Content-Type"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		parts = append(parts, part{header: next.Header, contentType: partType, body: string(data)})
+	}
+}
@@ -0,0 +1,116 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metrics
+
+import (
+	"github-updates/internal/pkg/configs"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/go-github/v33/github"
+)
+
+// Cadence computes the release timeline of the repository, the
+// oldest release first. A repository which never released is over
+// the SLA as well.
+func Cadence(
+	org string,
+	repo string,
+	releases []github.RepositoryRelease,
+	config configs.TimelineConfiguration,
+	now time.Time,
+) configs.RepositoryTimeline {
+	timeline := configs.RepositoryTimeline{
+		Repository: repo,
+		URL:        "https://github.com/" + org + "/" + repo,
+		Series:     []configs.TimelinePoint{},
+	}
+	for _, release := range releases {
+		if release.GetPrerelease() && !config.IncludePrereleases {
+			continue
+		}
+		date := release.GetPublishedAt().Time
+		if release.PublishedAt == nil {
+			date = release.GetCreatedAt().Time
+		}
+		timeline.Series = append(timeline.Series, configs.TimelinePoint{
+			Date:       date,
+			Tag:        release.GetTagName(),
+			Name:       release.GetName(),
+			URL:        release.GetHTMLURL(),
+			Prerelease: release.GetPrerelease(),
+		})
+	}
+	sort.SliceStable(timeline.Series, func(i, j int) bool {
+		return timeline.Series[i].Date.Before(timeline.Series[j].Date)
+	})
+
+	timeline.Releases = len(timeline.Series)
+	if timeline.Releases == 0 {
+		timeline.OverSLA = config.SLADays > 0
+		return timeline
+	}
+	for index := 1; index < len(timeline.Series); index++ {
+		days := roundDays(timeline.Series[index].Date.Sub(timeline.Series[index-1].Date))
+		timeline.Series[index].DaysSincePrevious = &days
+	}
+	first := timeline.Series[0].Date
+	last := timeline.Series[len(timeline.Series)-1].Date
+	timeline.FirstRelease = &first
+	timeline.LastRelease = &last
+	if timeline.Releases > 1 {
+		average := roundDays(last.Sub(first) / time.Duration(timeline.Releases-1))
+		timeline.AverageDaysBetween = &average
+	}
+	daysSinceLast := int(now.Sub(last).Hours() / 24)
+	timeline.DaysSinceLast = &daysSinceLast
+	timeline.OverSLA = config.SLADays > 0 && daysSinceLast > config.SLADays
+	return timeline
+}
+
+// PlaceTimeline sets the position of every release along the range
+// from the first release of the organization to now, and returns the
+// start of the range
+func PlaceTimeline(repositories []configs.RepositoryTimeline, now time.Time) *time.Time {
+	var since *time.Time
+	for _, repository := range repositories {
+		if repository.FirstRelease != nil && (since == nil || repository.FirstRelease.Before(*since)) {
+			first := *repository.FirstRelease
+			since = &first
+		}
+	}
+	if since == nil {
+		return nil
+	}
+	span := now.Sub(*since)
+	for _, repository := range repositories {
+		for index := range repository.Series {
+			position := 100.0
+			if span > 0 {
+				position = float64(repository.Series[index].Date.Sub(*since)) / float64(span) * 100
+			}
+			repository.Series[index].Position = math.Round(position*10) / 10
+		}
+	}
+	return since
+}
+
+// roundDays rounds the duration to a tenth of a day
+func roundDays(duration time.Duration) float64 {
+	return math.Round(duration.Hours()/24*10) / 10
+}
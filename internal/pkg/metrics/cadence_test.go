@@ -0,0 +1,120 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metrics
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github-updates/internal/pkg/configs"
+
+	"github.com/google/go-github/v33/github"
+)
+
+func release(tag string, published time.Time, prerelease bool) github.RepositoryRelease {
+	return github.RepositoryRelease{
+		TagName:     github.String(tag),
+		PublishedAt: &github.Timestamp{Time: published},
+		Prerelease:  github.Bool(prerelease),
+	}
+}
+
+func TestCadence(t *testing.T) {
+	now := time.Date(2021, 5, 3, 0, 0, 0, 0, time.UTC)
+	day := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+
+	t.Run("no releases", func(t *testing.T) {
+		if Cadence("org", "repo", nil, configs.TimelineConfiguration{}, now).OverSLA {
+			t.Error("over the SLA with no SLA set")
+		}
+		if !Cadence("org", "repo", nil, configs.TimelineConfiguration{SLADays: 30}, now).OverSLA {
+			t.Error("not over the SLA with no release")
+		}
+	})
+
+	t.Run("single release", func(t *testing.T) {
+		timeline := Cadence("org", "repo", []github.RepositoryRelease{release("v1", day(40), false)},
+			configs.TimelineConfiguration{SLADays: 30}, now)
+		if timeline.Releases != 1 || timeline.AverageDaysBetween != nil {
+			t.Errorf("got %v releases, %v days between, want 1 and none", timeline.Releases, timeline.AverageDaysBetween)
+		}
+		if timeline.DaysSinceLast == nil || *timeline.DaysSinceLast != 40 || !timeline.OverSLA {
+			t.Errorf("got %v days since the last release, over the SLA: %v, want 40 and over", timeline.DaysSinceLast, timeline.OverSLA)
+		}
+	})
+
+	// unsorted, with a prerelease in between
+	releases := []github.RepositoryRelease{
+		release("v3", day(10), false),
+		release("v1", day(70), false),
+		release("v3-rc1", day(15), true),
+		release("v2", day(40), false),
+	}
+
+	t.Run("prereleases excluded", func(t *testing.T) {
+		timeline := Cadence("org", "repo", releases, configs.TimelineConfiguration{SLADays: 30}, now)
+		var tags []string
+		for _, point := range timeline.Series {
+			tags = append(tags, point.Tag)
+		}
+		if !reflect.DeepEqual(tags, []string{"v1", "v2", "v3"}) {
+			t.Errorf("got the series %v, want the releases oldest first", tags)
+		}
+		if *timeline.Series[1].DaysSincePrevious != 30 || *timeline.Series[2].DaysSincePrevious != 30 {
+			t.Errorf("got %v and %v days between the releases, want 30",
+				*timeline.Series[1].DaysSincePrevious, *timeline.Series[2].DaysSincePrevious)
+		}
+		if *timeline.AverageDaysBetween != 30 || *timeline.DaysSinceLast != 10 || timeline.OverSLA {
+			t.Errorf("got %v days between, %v since the last, over the SLA: %v",
+				*timeline.AverageDaysBetween, *timeline.DaysSinceLast, timeline.OverSLA)
+		}
+	})
+
+	t.Run("prereleases included", func(t *testing.T) {
+		timeline := Cadence("org", "repo", releases, configs.TimelineConfiguration{IncludePrereleases: true}, now)
+		if timeline.Releases != 4 || *timeline.AverageDaysBetween != 20 {
+			t.Errorf("got %v releases, %v days between, want 4 and 20", timeline.Releases, *timeline.AverageDaysBetween)
+		}
+	})
+}
+
+func TestPlaceTimeline(t *testing.T) {
+	now := time.Date(2021, 5, 3, 0, 0, 0, 0, time.UTC)
+	day := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+	repositories := []configs.RepositoryTimeline{
+		Cadence("org", "late", []github.RepositoryRelease{release("v1", day(25), false)}, configs.TimelineConfiguration{}, now),
+		Cadence("org", "early", []github.RepositoryRelease{release("v1", day(100), false), release("v2", day(0), false)}, configs.TimelineConfiguration{}, now),
+		Cadence("org", "none", nil, configs.TimelineConfiguration{}, now),
+	}
+	since := PlaceTimeline(repositories, now)
+	if since == nil || !since.Equal(day(100)) {
+		t.Fatalf("the timeline starts at %v, want the first release of the organization", since)
+	}
+	positions := []float64{
+		repositories[0].Series[0].Position,
+		repositories[1].Series[0].Position,
+		repositories[1].Series[1].Position,
+	}
+	if !reflect.DeepEqual(positions, []float64{75, 0, 100}) {
+		t.Errorf("got the positions %v, want [75 0 100]", positions)
+	}
+
+	if PlaceTimeline(repositories[2:], now) != nil {
+		t.Error("a timeline with no release has a start")
+	}
+}
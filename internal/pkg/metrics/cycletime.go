@@ -0,0 +1,106 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package metrics computes the health figures of the repositories
+// out of the collected data
+package metrics
+
+import (
+	"github-updates/internal/pkg/configs"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/go-github/v33/github"
+)
+
+// CycleTime of the PR from its reviews. Pending reviews and the
+// reviews of the author on their own PR are not counted.
+func CycleTime(pr github.PullRequest, reviews []github.PullRequestReview) configs.CycleTime {
+	var cycleTime configs.CycleTime
+	var firstReview time.Time
+	rounds := map[string]bool{}
+	for _, review := range reviews {
+		if review.GetState() == "PENDING" || review.GetUser().GetLogin() == pr.GetUser().GetLogin() {
+			continue
+		}
+		cycleTime.Reviews++
+		rounds[review.GetCommitID()] = true
+		if firstReview.IsZero() || review.GetSubmittedAt().Before(firstReview) {
+			firstReview = review.GetSubmittedAt()
+		}
+	}
+	cycleTime.ReviewRounds = len(rounds)
+	if !firstReview.IsZero() {
+		cycleTime.TimeToFirstReviewHours = hours(firstReview.Sub(pr.GetCreatedAt()))
+	}
+	if pr.MergedAt != nil {
+		cycleTime.TimeToMergeHours = hours(pr.GetMergedAt().Sub(pr.GetCreatedAt()))
+	}
+	return cycleTime
+}
+
+// Summarize the cycle time of the PRs of a repository
+func Summarize(cycleTimes map[int]configs.CycleTime) configs.CycleTimeSummary {
+	var firstReview, merge, rounds, reviews []float64
+	for _, cycleTime := range cycleTimes {
+		if cycleTime.TimeToFirstReviewHours != nil {
+			firstReview = append(firstReview, *cycleTime.TimeToFirstReviewHours)
+		}
+		if cycleTime.TimeToMergeHours != nil {
+			merge = append(merge, *cycleTime.TimeToMergeHours)
+		}
+		rounds = append(rounds, float64(cycleTime.ReviewRounds))
+		reviews = append(reviews, float64(cycleTime.Reviews))
+	}
+	return configs.CycleTimeSummary{
+		PRs:               len(cycleTimes),
+		Merged:            len(merge),
+		TimeToFirstReview: Distribution(firstReview),
+		TimeToMerge:       Distribution(merge),
+		ReviewRounds:      Distribution(rounds),
+		Reviews:           Distribution(reviews),
+	}
+}
+
+// Distribution returns the median and the 90th percentile of the
+// values with the nearest rank method, all zero without values
+func Distribution(values []float64) configs.Percentiles {
+	if len(values) == 0 {
+		return configs.Percentiles{}
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	return configs.Percentiles{
+		Count:  len(sorted),
+		Median: nearestRank(sorted, 50),
+		P90:    nearestRank(sorted, 90),
+	}
+}
+
+func nearestRank(sorted []float64, percentile float64) float64 {
+	rank := int(math.Ceil(percentile / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// hours rounded to a tenth, enough for the reports
+func hours(duration time.Duration) *float64 {
+	value := math.Round(duration.Hours()*10) / 10
+	return &value
+}
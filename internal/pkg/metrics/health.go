@@ -0,0 +1,129 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metrics
+
+import (
+	"github-updates/internal/pkg/configs"
+	"math"
+	"time"
+)
+
+// DefaultScorecardWeights are used when no weight is configured
+var DefaultScorecardWeights = configs.ScorecardWeights{
+	Activity:         25,
+	ReleaseCadence:   15,
+	IssueGrowth:      15,
+	BusFactor:        20,
+	CommunityFiles:   15,
+	BranchProtection: 10,
+}
+
+// DefaultScorecardThresholds apply to the thresholds not configured
+var DefaultScorecardThresholds = configs.ScorecardThresholds{
+	Activity:    30,
+	ReleaseDays: 90,
+	BusFactor:   3,
+}
+
+// ScorecardThresholds fills the thresholds not configured with the
+// defaults, a max issue growth of 0 is a valid threshold
+func ScorecardThresholds(thresholds configs.ScorecardThresholds) configs.ScorecardThresholds {
+	if thresholds.Activity <= 0 {
+		thresholds.Activity = DefaultScorecardThresholds.Activity
+	}
+	if thresholds.ReleaseDays <= 0 {
+		thresholds.ReleaseDays = DefaultScorecardThresholds.ReleaseDays
+	}
+	if thresholds.BusFactor <= 0 {
+		thresholds.BusFactor = DefaultScorecardThresholds.BusFactor
+	}
+	return thresholds
+}
+
+// Score rates every signal between 0 and 1 against its threshold
+// and weighs it, the total is out of 100 whatever the weights are
+func Score(
+	signals configs.HealthSignals,
+	weights configs.ScorecardWeights,
+	thresholds configs.ScorecardThresholds,
+	now time.Time,
+) configs.HealthScore {
+	if weights == (configs.ScorecardWeights{}) {
+		weights = DefaultScorecardWeights
+	}
+	thresholds = ScorecardThresholds(thresholds)
+
+	activity := ratio(signals.Commits+signals.MergedPRs, thresholds.Activity)
+
+	// full marks for a release within the days, none at twice as long
+	releaseCadence := 0.0
+	if signals.LastRelease != nil {
+		days := now.Sub(*signals.LastRelease).Hours() / 24
+		limit := float64(thresholds.ReleaseDays)
+		releaseCadence = math.Max(0, math.Min(1, 2-days/limit))
+	}
+
+	// full marks while the backlog does not grow more than allowed
+	issueGrowth := 1.0
+	growth := signals.IssuesOpened - signals.IssuesClosed
+	if growth > thresholds.MaxIssueGrowth && signals.IssuesOpened > 0 {
+		issueGrowth = math.Max(0, 1-float64(growth-thresholds.MaxIssueGrowth)/float64(signals.IssuesOpened))
+	}
+
+	busFactor := ratio(signals.Mergers, thresholds.BusFactor)
+
+	files := 0
+	for _, present := range []bool{signals.Readme, signals.License, signals.Contributing, signals.Security} {
+		if present {
+			files++
+		}
+	}
+	communityFiles := float64(files) / 4
+
+	branchProtection := 0.0
+	if signals.BranchProtection {
+		branchProtection = 1
+	}
+
+	total := weights.Activity + weights.ReleaseCadence + weights.IssueGrowth +
+		weights.BusFactor + weights.CommunityFiles + weights.BranchProtection
+	points := func(value float64, weight float64) float64 {
+		if total == 0 {
+			return 0
+		}
+		return math.Round(value*weight/total*1000) / 10
+	}
+	score := configs.HealthScore{
+		Activity:         points(activity, weights.Activity),
+		ReleaseCadence:   points(releaseCadence, weights.ReleaseCadence),
+		IssueGrowth:      points(issueGrowth, weights.IssueGrowth),
+		BusFactor:        points(busFactor, weights.BusFactor),
+		CommunityFiles:   points(communityFiles, weights.CommunityFiles),
+		BranchProtection: points(branchProtection, weights.BranchProtection),
+	}
+	score.Total = math.Round((score.Activity+score.ReleaseCadence+score.IssueGrowth+
+		score.BusFactor+score.CommunityFiles+score.BranchProtection)*10) / 10
+	return score
+}
+
+// ratio of the value to the threshold, at most 1
+func ratio(value int, threshold int) float64 {
+	if threshold <= 0 {
+		return 1
+	}
+	return math.Min(1, float64(value)/float64(threshold))
+}
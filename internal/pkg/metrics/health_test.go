@@ -0,0 +1,122 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github-updates/internal/pkg/configs"
+)
+
+func TestScore(t *testing.T) {
+	now := time.Date(2021, 5, 3, 0, 0, 0, 0, time.UTC)
+	recent := now.AddDate(0, 0, -10)
+	// 135 days is one and a half times the default 90 days
+	late := now.AddDate(0, 0, -135)
+	healthy := configs.HealthSignals{
+		Commits:          20,
+		MergedPRs:        10,
+		Mergers:          3,
+		LastRelease:      &recent,
+		IssuesOpened:     10,
+		IssuesClosed:     10,
+		Readme:           true,
+		License:          true,
+		Contributing:     true,
+		Security:         true,
+		BranchProtection: true,
+	}
+	with := func(change func(signals *configs.HealthSignals)) configs.HealthSignals {
+		signals := healthy
+		change(&signals)
+		return signals
+	}
+	tests := []struct {
+		name       string
+		signals    configs.HealthSignals
+		weights    configs.ScorecardWeights
+		thresholds configs.ScorecardThresholds
+		want       configs.HealthScore
+	}{
+		{
+			name:    "default weights",
+			signals: healthy,
+			want:    configs.HealthScore{Total: 100, Activity: 25, ReleaseCadence: 15, IssueGrowth: 15, BusFactor: 20, CommunityFiles: 15, BranchProtection: 10},
+		},
+		{
+			name:    "a single weight",
+			signals: healthy,
+			weights: configs.ScorecardWeights{BusFactor: 2},
+			want:    configs.HealthScore{Total: 100, BusFactor: 100},
+		},
+		{
+			name:    "no signal",
+			signals: configs.HealthSignals{},
+			// an empty backlog does not grow
+			want: configs.HealthScore{Total: 15, IssueGrowth: 15},
+		},
+		{
+			name: "no release",
+			signals: with(func(signals *configs.HealthSignals) {
+				signals.LastRelease = nil
+			}),
+			want: configs.HealthScore{Total: 85, Activity: 25, IssueGrowth: 15, BusFactor: 20, CommunityFiles: 15, BranchProtection: 10},
+		},
+		{
+			name: "late release",
+			signals: with(func(signals *configs.HealthSignals) {
+				signals.LastRelease = &late
+			}),
+			want: configs.HealthScore{Total: 92.5, Activity: 25, ReleaseCadence: 7.5, IssueGrowth: 15, BusFactor: 20, CommunityFiles: 15, BranchProtection: 10},
+		},
+		{
+			name: "no max issue growth",
+			signals: with(func(signals *configs.HealthSignals) {
+				signals.IssuesClosed = 5
+			}),
+			thresholds: configs.ScorecardThresholds{MaxIssueGrowth: 0},
+			want:       configs.HealthScore{Total: 92.5, Activity: 25, ReleaseCadence: 15, IssueGrowth: 7.5, BusFactor: 20, CommunityFiles: 15, BranchProtection: 10},
+		},
+		{
+			name: "growth within the max",
+			signals: with(func(signals *configs.HealthSignals) {
+				signals.IssuesClosed = 5
+			}),
+			thresholds: configs.ScorecardThresholds{MaxIssueGrowth: 5},
+			want:       configs.HealthScore{Total: 100, Activity: 25, ReleaseCadence: 15, IssueGrowth: 15, BusFactor: 20, CommunityFiles: 15, BranchProtection: 10},
+		},
+		{
+			name: "half the activity and the mergers",
+			signals: with(func(signals *configs.HealthSignals) {
+				signals.Commits = 5
+				signals.Mergers = 1
+				signals.Security = false
+			}),
+			thresholds: configs.ScorecardThresholds{BusFactor: 2},
+			want:       configs.HealthScore{Total: 73.8, Activity: 12.5, ReleaseCadence: 15, IssueGrowth: 15, BusFactor: 10, CommunityFiles: 11.3, BranchProtection: 10},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			score := Score(test.signals, test.weights, test.thresholds, now)
+			if score != test.want {
+				t.Errorf("got %+v, want %+v", score, test.want)
+			}
+		})
+	}
+}
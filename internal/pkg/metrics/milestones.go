@@ -0,0 +1,50 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metrics
+
+import (
+	"github-updates/internal/pkg/configs"
+	"math"
+	"time"
+
+	"github.com/google/go-github/v33/github"
+)
+
+// MilestoneProgress is the completion of the open milestone, a
+// milestone with no issue is not complete. It is overdue once its
+// due date has passed.
+func MilestoneProgress(milestone github.Milestone, closedRecently int, now time.Time) configs.Milestone {
+	progress := configs.Milestone{
+		Number:         milestone.GetNumber(),
+		Title:          milestone.GetTitle(),
+		URL:            milestone.GetHTMLURL(),
+		OpenIssues:     milestone.GetOpenIssues(),
+		ClosedIssues:   milestone.GetClosedIssues(),
+		ClosedRecently: closedRecently,
+	}
+	if total := progress.OpenIssues + progress.ClosedIssues; total > 0 {
+		progress.PercentComplete = math.Round(float64(progress.ClosedIssues)/float64(total)*1000) / 10
+	}
+	if milestone.DueOn != nil {
+		dueOn := milestone.GetDueOn()
+		progress.DueOn = &dueOn
+		daysLeft := int(math.Ceil(dueOn.Sub(now).Hours() / 24))
+		progress.DaysLeft = &daysLeft
+		progress.Overdue = dueOn.Before(now)
+	}
+	return progress
+}
@@ -0,0 +1,155 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package publishers posts the weekly digest of the collected
+// activity to the chat and social platforms
+package publishers
+
+import (
+	"github-updates/internal/pkg/configs"
+	"sort"
+	"time"
+)
+
+// Digest is the short summary of a run posted to the channels
+type Digest struct {
+	Releases  []DigestRelease
+	MergedPRs []ProjectCount
+	NewIssues []DigestIssue
+}
+
+// DigestRelease is a release in the digest
+type DigestRelease struct {
+	Organization string
+	Repository   string
+	Name         string
+	TagName      string
+	URL          string
+	PublishedAt  time.Time
+}
+
+// ProjectCount is the number of merged PRs of a repository
+type ProjectCount struct {
+	Organization string
+	Repository   string
+	Count        int
+}
+
+// DigestIssue is a newly opened issue in the digest
+type DigestIssue struct {
+	Organization string
+	Repository   string
+	Number       int
+	Title        string
+	URL          string
+	CreatedAt    time.Time
+}
+
+// NewDigest picks the latest releases, the repositories with the
+// most merged PRs and the latest issues opened since the given time,
+// each list is limited by the configuration
+func NewDigest(
+	digestConfig configs.DigestConfiguration,
+	since time.Time,
+	prs []configs.PullRequestDetails,
+	releases []configs.ReleaseDetails,
+	issues []configs.IssueDetails,
+) Digest {
+	var digest Digest
+	for _, org := range releases {
+		for _, repo := range org.ReleaseRepoLists {
+			for _, release := range repo.Releases {
+				name := release.GetName()
+				if name == "" {
+					name = release.GetTagName()
+				}
+				digest.Releases = append(digest.Releases, DigestRelease{
+					Organization: org.Organization,
+					Repository:   repo.Repository,
+					Name:         name,
+					TagName:      release.GetTagName(),
+					URL:          release.GetHTMLURL(),
+					PublishedAt:  release.GetPublishedAt().Time,
+				})
+			}
+		}
+	}
+	sort.SliceStable(digest.Releases, func(first, second int) bool {
+		return digest.Releases[first].PublishedAt.After(digest.Releases[second].PublishedAt)
+	})
+	digest.Releases = limitReleases(digest.Releases, digestConfig.TopReleases)
+
+	for _, org := range prs {
+		for _, repo := range org.PrRepoLists {
+			merged := 0
+			for _, pr := range repo.PRs {
+				if pr.MergedAt != nil {
+					merged++
+				}
+			}
+			if merged != 0 {
+				digest.MergedPRs = append(digest.MergedPRs, ProjectCount{
+					Organization: org.Organization,
+					Repository:   repo.Repository,
+					Count:        merged,
+				})
+			}
+		}
+	}
+	sort.SliceStable(digest.MergedPRs, func(first, second int) bool {
+		return digest.MergedPRs[first].Count > digest.MergedPRs[second].Count
+	})
+	if digestConfig.TopProjects > 0 && len(digest.MergedPRs) > digestConfig.TopProjects {
+		digest.MergedPRs = digest.MergedPRs[:digestConfig.TopProjects]
+	}
+
+	for _, org := range issues {
+		for _, repo := range org.IssueLists {
+			for _, issue := range repo.Issues {
+				if issue.GetCreatedAt().Before(since) {
+					continue
+				}
+				digest.NewIssues = append(digest.NewIssues, DigestIssue{
+					Organization: org.Organization,
+					Repository:   repo.Repository,
+					Number:       issue.GetNumber(),
+					Title:        issue.GetTitle(),
+					URL:          issue.GetHTMLURL(),
+					CreatedAt:    issue.GetCreatedAt(),
+				})
+			}
+		}
+	}
+	sort.SliceStable(digest.NewIssues, func(first, second int) bool {
+		return digest.NewIssues[first].CreatedAt.After(digest.NewIssues[second].CreatedAt)
+	})
+	if digestConfig.TopIssues > 0 && len(digest.NewIssues) > digestConfig.TopIssues {
+		digest.NewIssues = digest.NewIssues[:digestConfig.TopIssues]
+	}
+	return digest
+}
+
+func limitReleases(releases []DigestRelease, limit int) []DigestRelease {
+	if limit > 0 && len(releases) > limit {
+		return releases[:limit]
+	}
+	return releases
+}
+
+// IsEmpty tells if there is nothing to post
+func (d Digest) IsEmpty() bool {
+	return len(d.Releases) == 0 && len(d.MergedPRs) == 0 && len(d.NewIssues) == 0
+}
@@ -0,0 +1,111 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package publishers
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Discord limits, see https://discord.com/developers/docs/resources/channel#embed-limits
+const (
+	discordMaxEmbeds      = 10
+	discordMaxTitle       = 256
+	discordMaxDescription = 4096
+	discordMaxMessageText = 6000
+	// Hyperledger teal
+	discordColor = 0x21adad
+)
+
+type discordMessage struct {
+	Content string         `json:"content,omitempty"`
+	Embeds  []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Color       int    `json:"color,omitempty"`
+}
+
+var discordEscaper = strings.NewReplacer("[", "\\[", "]", "\\]", "*", "\\*", "_", "\\_", "`", "\\`")
+
+// discordMessages lays the digest out in embeds, one or more for
+// each section, grouped into messages within the Discord limits
+func discordMessages(title string, digest Digest) []discordMessage {
+	var embeds []discordEmbed
+	addSection := func(heading string, lines []string) {
+		for _, chunk := range chunkLines(lines, discordMaxDescription) {
+			embeds = append(embeds, discordEmbed{
+				Title:       truncate(heading, discordMaxTitle),
+				Description: chunk,
+				Color:       discordColor,
+			})
+		}
+	}
+
+	var lines []string
+	for _, release := range digest.Releases {
+		lines = append(lines, fmt.Sprintf("• [%v/%v %v](%v)",
+			release.Organization, release.Repository, discordEscaper.Replace(release.Name), release.URL))
+	}
+	addSection("New releases", lines)
+
+	lines = nil
+	for _, project := range digest.MergedPRs {
+		lines = append(lines, fmt.Sprintf("• %v/%v: %v merged", project.Organization, project.Repository, project.Count))
+	}
+	addSection("Merged pull requests", lines)
+
+	lines = nil
+	for _, issue := range digest.NewIssues {
+		lines = append(lines, fmt.Sprintf("• [%v/%v#%v](%v) %v",
+			issue.Organization, issue.Repository, issue.Number, issue.URL, discordEscaper.Replace(issue.Title)))
+	}
+	addSection("New good first issues", lines)
+
+	content := truncate(title, 2000)
+	var messages []discordMessage
+	current := discordMessage{Content: content}
+	size := 0
+	for _, embed := range embeds {
+		embedSize := utf8.RuneCountInString(embed.Title) + utf8.RuneCountInString(embed.Description)
+		if len(current.Embeds) == discordMaxEmbeds || size+embedSize > discordMaxMessageText {
+			messages = append(messages, current)
+			current = discordMessage{}
+			size = 0
+		}
+		current.Embeds = append(current.Embeds, embed)
+		size += embedSize
+	}
+	if len(current.Embeds) != 0 {
+		messages = append(messages, current)
+	}
+	return messages
+}
+
+// PublishDiscord posts the digest to the Discord webhook
+func PublishDiscord(webhookURL string, title string, digest Digest) error {
+	for _, message := range discordMessages(title, digest) {
+		_, err := postJSON("POST", webhookURL, nil, message)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
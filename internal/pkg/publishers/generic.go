@@ -0,0 +1,123 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package publishers
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github-updates/internal/pkg/configs"
+	"github-updates/internal/pkg/templates"
+	"github-updates/internal/pkg/utils"
+	"path/filepath"
+	"text/template"
+)
+
+// Auth types of the generic webhook
+const (
+	AuthBearer = "bearer"
+	AuthBasic  = "basic"
+	AuthHeader = "header"
+)
+
+// templateData is available to the webhook body templates
+type templateData struct {
+	Title string
+	Digest
+}
+
+// PublishWebhook renders the body template of the webhook with the
+// digest and sends it with the configured headers and auth
+func PublishWebhook(webhook configs.WebhookConfiguration, title string, digest Digest) error {
+	var err error
+	if webhook.Preset == PresetMatrix {
+		webhook, err = matrixWebhook(webhook)
+		if err != nil {
+			return err
+		}
+	}
+	if webhook.URL == "" {
+		return fmt.Errorf("webhook %v has no url", webhook.Name)
+	}
+	t, err := bodyTemplate(webhook)
+	if err != nil {
+		return err
+	}
+	var body bytes.Buffer
+	err = t.Execute(&body, templateData{Title: title, Digest: digest})
+	if err != nil {
+		return err
+	}
+	if !json.Valid(body.Bytes()) {
+		return fmt.Errorf("webhook %v template did not render valid JSON", webhook.Name)
+	}
+
+	headers := map[string]string{}
+	for key, value := range webhook.Headers {
+		headers[key] = value
+	}
+	err = setAuth(headers, webhook.Auth)
+	if err != nil {
+		return err
+	}
+	method := webhook.Method
+	if method == "" {
+		method = "POST"
+	}
+	_, err = post(method, webhook.URL, headers, body.Bytes())
+	return err
+}
+
+func bodyTemplate(webhook configs.WebhookConfiguration) (*template.Template, error) {
+	if webhook.Template != "" {
+		return template.New(filepath.Base(webhook.Template)).Funcs(templates.Funcs).ParseFiles(webhook.Template)
+	}
+	text, ok := presetTemplates[webhook.Preset]
+	if !ok {
+		return nil, fmt.Errorf("webhook %v needs a template or a preset", webhook.Name)
+	}
+	return template.New(webhook.Preset).Funcs(templates.Funcs).Parse(text)
+}
+
+// setAuth adds the authorization header, the secret is always read
+// from the environment variable named in the configuration
+func setAuth(headers map[string]string, auth configs.WebhookAuth) error {
+	if auth.Type == "" {
+		return nil
+	}
+	secret := utils.GetEnvOrDefault(auth.SecretEnv, "")
+	if secret == "" {
+		return fmt.Errorf("the %v auth secret is not set in %v", auth.Type, auth.SecretEnv)
+	}
+	switch auth.Type {
+	case AuthBearer:
+		headers["Authorization"] = "Bearer " + secret
+	case AuthBasic:
+		headers["Authorization"] = "Basic " +
+			base64.StdEncoding.EncodeToString([]byte(auth.Username+":"+secret))
+	case AuthHeader:
+		if auth.Header == "" {
+			return errors.New("header auth needs the header name")
+		}
+		headers[auth.Header] = secret
+	default:
+		return fmt.Errorf("unknown webhook auth %v", auth.Type)
+	}
+	return nil
+}
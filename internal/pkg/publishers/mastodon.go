@@ -0,0 +1,306 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package publishers
+
+import (
+	"encoding/json"
+	"fmt"
+	"github-updates/internal/pkg/configs"
+	"github-updates/internal/pkg/templates"
+	"io/ioutil"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// defaultStatusLength is the character limit of a stock instance
+	defaultStatusLength = 500
+	// mastodonURLLength is what Mastodon counts for any link
+	mastodonURLLength = 23
+)
+
+// Announcement is a release to post
+type Announcement struct {
+	ID           int64
+	Organization string
+	Repository   string
+	Name         string
+	TagName      string
+	URL          string
+}
+
+type mastodonStatus struct {
+	Status      string `json:"status"`
+	Visibility  string `json:"visibility,omitempty"`
+	InReplyToID string `json:"in_reply_to_id,omitempty"`
+}
+
+type mastodonPosted struct {
+	ID string `json:"id"`
+}
+
+// postedState records the releases already announced
+type postedState struct {
+	Releases []postedRelease `json:"releases"`
+}
+
+// postedRelease is a release announced at PostedAt
+type postedRelease struct {
+	ID       int64     `json:"id"`
+	PostedAt time.Time `json:"postedAt"`
+}
+
+// Announcements lists the releases of the report, oldest first so
+// that the timeline reads in the order of publishing
+func Announcements(releases []configs.ReleaseDetails) []Announcement {
+	type dated struct {
+		Announcement
+		published int64
+	}
+	var all []dated
+	for _, org := range releases {
+		for _, repo := range org.ReleaseRepoLists {
+			for _, release := range repo.Releases {
+				name := release.GetName()
+				if name == "" {
+					name = release.GetTagName()
+				}
+				all = append(all, dated{
+					Announcement: Announcement{
+						ID:           release.GetID(),
+						Organization: org.Organization,
+						Repository:   repo.Repository,
+						Name:         name,
+						TagName:      release.GetTagName(),
+						URL:          release.GetHTMLURL(),
+					},
+					published: release.GetPublishedAt().Unix(),
+				})
+			}
+		}
+	}
+	sort.SliceStable(all, func(first, second int) bool {
+		return all[first].published < all[second].published
+	})
+	announcements := make([]Announcement, len(all))
+	for index, release := range all {
+		announcements[index] = release.Announcement
+	}
+	return announcements
+}
+
+// PublishMastodon posts the releases which were not announced yet,
+// the state file is updated after every status so that a failed
+// run does not announce a release twice. The releases posted before
+// the reported days can not be listed again and are dropped from the
+// state.
+func PublishMastodon(
+	mastodon configs.MastodonConfiguration,
+	token string,
+	announcements []Announcement,
+	days int,
+) error {
+	if mastodon.Instance == "" || token == "" {
+		return fmt.Errorf("mastodon needs the instance and the access token")
+	}
+	state, err := readState(mastodon.StateFile)
+	if err != nil {
+		return err
+	}
+	since := time.Now().AddDate(0, 0, -days)
+	kept := state.Releases[:0]
+	posted := map[int64]bool{}
+	for _, release := range state.Releases {
+		if release.PostedAt.Before(since) {
+			continue
+		}
+		kept = append(kept, release)
+		posted[release.ID] = true
+	}
+	if len(kept) != len(state.Releases) {
+		log.Printf("Dropping %v releases posted before %v from the Mastodon state",
+			len(state.Releases)-len(kept), since.Format("2006-01-02"))
+		state.Releases = kept
+		err = writeState(mastodon.StateFile, state)
+		if err != nil {
+			return err
+		}
+	}
+	var pending []Announcement
+	for _, announcement := range announcements {
+		if !posted[announcement.ID] {
+			pending = append(pending, announcement)
+		}
+	}
+	if len(pending) == 0 {
+		log.Println("No new releases to announce on Mastodon")
+		return nil
+	}
+
+	limit := mastodon.MaxCharacters
+	if limit == 0 {
+		limit = defaultStatusLength
+	}
+	endpoint := strings.TrimRight(mastodon.Instance, "/") + "/api/v1/statuses"
+	postStatus := func(text string, replyTo string, idempotencyKey string) (string, error) {
+		contents, err := postJSON("POST", endpoint, map[string]string{
+			"Authorization":   "Bearer " + token,
+			"Idempotency-Key": idempotencyKey,
+		}, mastodonStatus{Status: text, Visibility: mastodon.Visibility, InReplyToID: replyTo})
+		if err != nil {
+			return "", err
+		}
+		var status mastodonPosted
+		err = json.Unmarshal(contents, &status)
+		return status.ID, err
+	}
+
+	replyTo := ""
+	if mastodon.Mode == configs.MastodonModeThread {
+		intro := threadIntro(len(pending), days)
+		if mastodon.ThreadTitle != "" {
+			intro = mastodon.ThreadTitle + "\n\n" + intro
+		}
+		intro = appendHashtags(intro, mastodon.Hashtags, limit)
+		replyTo, err = postStatus(intro, "", fmt.Sprintf("thread-%v", pending[0].ID))
+		if err != nil {
+			return err
+		}
+	}
+	for _, announcement := range pending {
+		text := StatusText(announcement, mastodon.Hashtags, limit)
+		id, err := postStatus(text, replyTo, fmt.Sprintf("release-%v", announcement.ID))
+		if err != nil {
+			return err
+		}
+		if mastodon.Mode == configs.MastodonModeThread {
+			replyTo = id
+		}
+		state.Releases = append(state.Releases, postedRelease{ID: announcement.ID, PostedAt: time.Now().UTC()})
+		err = writeState(mastodon.StateFile, state)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// threadIntro counts the releases of the thread over the reported
+// days, such as "2 new releases in the last 7 days 🧵"
+func threadIntro(count int, days int) string {
+	intro := "1 new release"
+	if count != 1 {
+		intro = fmt.Sprintf("%v new releases", count)
+	}
+	if period := templates.Period(days); period != "" {
+		intro += " in " + period
+	}
+	return intro + " 🧵"
+}
+
+// StatusText writes the announcement within the character limit,
+// the release name is shortened when it does not fit
+func StatusText(announcement Announcement, hashtags []string, limit int) string {
+	name := announcement.Name
+	if announcement.TagName != "" && announcement.TagName != name {
+		name += " (" + announcement.TagName + ")"
+	}
+	repository := announcement.Organization + "/" + announcement.Repository
+	// "🚀 " + repository + " " + name + " is out!\n\n" + url
+	fixed := 2 + utf8.RuneCountInString(repository) + 1 + len(" is out!\n\n") + mastodonURLLength
+	if available := limit - fixed; utf8.RuneCountInString(name) > available {
+		if available < 2 {
+			name = ""
+		} else {
+			name = truncate(name, available)
+		}
+	}
+	text := fmt.Sprintf("🚀 %v is out!\n\n%v", repository, announcement.URL)
+	if name != "" {
+		text = fmt.Sprintf("🚀 %v %v is out!\n\n%v", repository, name, announcement.URL)
+	}
+	return appendHashtags(text, hashtags, limit)
+}
+
+// appendHashtags adds the hashtags which still fit in the limit
+func appendHashtags(text string, hashtags []string, limit int) string {
+	length := statusLength(text)
+	separator := "\n\n"
+	for _, hashtag := range hashtags {
+		tag := "#" + strings.TrimPrefix(hashtag, "#")
+		if length+utf8.RuneCountInString(separator+tag) > limit {
+			break
+		}
+		text += separator + tag
+		length += utf8.RuneCountInString(separator + tag)
+		separator = " "
+	}
+	return text
+}
+
+// statusLength counts the characters as Mastodon does, every link
+// is counted as mastodonURLLength
+func statusLength(text string) int {
+	length := 0
+	for index, word := range strings.Split(text, " ") {
+		if index != 0 {
+			length++
+		}
+		for lineIndex, line := range strings.Split(word, "\n") {
+			if lineIndex != 0 {
+				length++
+			}
+			if strings.HasPrefix(line, "http://") || strings.HasPrefix(line, "https://") {
+				length += mastodonURLLength
+			} else {
+				length += utf8.RuneCountInString(line)
+			}
+		}
+	}
+	return length
+}
+
+func readState(fileName string) (postedState, error) {
+	var state postedState
+	contents, err := ioutil.ReadFile(fileName)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return state, err
+	}
+	err = json.Unmarshal(contents, &state)
+	return state, err
+}
+
+func writeState(fileName string, state postedState) error {
+	contents, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	// replace the file at once so that a crash leaves the old state
+	temporary := fileName + ".tmp"
+	err = ioutil.WriteFile(temporary, contents, 0644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(temporary, fileName)
+}
@@ -0,0 +1,196 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package publishers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github-updates/internal/pkg/configs"
+)
+
+// mastodonStub is a local stand-in for the statuses API, it answers
+// every status with the next id
+type mastodonStub struct {
+	*httptest.Server
+	mutex           sync.Mutex
+	statuses        []mastodonStatus
+	idempotencyKeys []string
+}
+
+func newMastodonStub(t *testing.T) *mastodonStub {
+	stub := &mastodonStub{}
+	stub.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, request *http.Request) {
+		if request.URL.Path != "/api/v1/statuses" || request.Method != "POST" {
+			http.NotFound(w, request)
+			return
+		}
+		if request.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var status mastodonStatus
+		err := json.NewDecoder(request.Body).Decode(&status)
+		if err != nil {
+			t.Error(err)
+		}
+		stub.mutex.Lock()
+		stub.statuses = append(stub.statuses, status)
+		stub.idempotencyKeys = append(stub.idempotencyKeys, request.Header.Get("Idempotency-Key"))
+		id := len(stub.statuses)
+		stub.mutex.Unlock()
+		fmt.Fprintf(w, `{"id": "%v"}`, id)
+	}))
+	t.Cleanup(stub.Close)
+	return stub
+}
+
+func testAnnouncements() []Announcement {
+	return []Announcement{
+		{ID: 1, Organization: "hyperledger", Repository: "fabric", Name: "v2.3.2", TagName: "v2.3.2", URL: "https://github.com/hyperledger/fabric/releases/tag/v2.3.2"},
+		{ID: 2, Organization: "hyperledger", Repository: "besu", Name: "Besu 21.1", TagName: "21.1.0", URL: "https://github.com/hyperledger/besu/releases/tag/21.1.0"},
+	}
+}
+
+func TestPublishMastodonThread(t *testing.T) {
+	stub := newMastodonStub(t)
+	mastodon := configs.MastodonConfiguration{
+		Instance:   stub.URL + "/",
+		Mode:       configs.MastodonModeThread,
+		Visibility: "unlisted",
+		Hashtags:   []string{"Hyperledger"},
+		StateFile:  filepath.Join(t.TempDir(), "state.json"),
+	}
+	err := PublishMastodon(mastodon, "token", testAnnouncements(), 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stub.statuses) != 3 {
+		t.Fatalf("got %v statuses, want the intro and 2 releases", len(stub.statuses))
+	}
+	if stub.statuses[0].Status != "2 new releases in the last 7 days 🧵\n\n#Hyperledger" || stub.statuses[0].InReplyToID != "" {
+		t.Errorf("got intro %+v", stub.statuses[0])
+	}
+	for index, reply := range []string{"1", "2"} {
+		status := stub.statuses[index+1]
+		if status.InReplyToID != reply || status.Visibility != "unlisted" {
+			t.Errorf("status %v is %+v, want a reply to %v", index+1, status, reply)
+		}
+	}
+	if want := "🚀 hyperledger/besu Besu 21.1 (21.1.0) is out!\n\nhttps://github.com/hyperledger/besu/releases/tag/21.1.0\n\n#Hyperledger"; stub.statuses[2].Status != want {
+		t.Errorf("got status %q, want %q", stub.statuses[2].Status, want)
+	}
+	if strings.Join(stub.idempotencyKeys, ",") != "thread-1,release-1,release-2" {
+		t.Errorf("got idempotency keys %v", stub.idempotencyKeys)
+	}
+
+	// the releases in the state are not announced again
+	err = PublishMastodon(mastodon, "token", testAnnouncements(), 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stub.statuses) != 3 {
+		t.Errorf("got %v statuses after the second run, want no new one", len(stub.statuses))
+	}
+}
+
+func TestPublishMastodonSingleRelease(t *testing.T) {
+	stub := newMastodonStub(t)
+	mastodon := configs.MastodonConfiguration{
+		Instance:  stub.URL,
+		Mode:      configs.MastodonModeThread,
+		StateFile: filepath.Join(t.TempDir(), "state.json"),
+	}
+	err := PublishMastodon(mastodon, "token", testAnnouncements()[:1], 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stub.statuses) == 0 || stub.statuses[0].Status != "1 new release in the last day 🧵" {
+		t.Errorf("got statuses %+v", stub.statuses)
+	}
+}
+
+func TestPublishMastodonPrunesState(t *testing.T) {
+	stub := newMastodonStub(t)
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	now := time.Now().UTC()
+	err := writeState(stateFile, postedState{Releases: []postedRelease{
+		{ID: 1, PostedAt: now.AddDate(0, 0, -30)},
+		{ID: 2, PostedAt: now.AddDate(0, 0, -1)},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mastodon := configs.MastodonConfiguration{Instance: stub.URL, StateFile: stateFile}
+	err = PublishMastodon(mastodon, "token", testAnnouncements()[1:], 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stub.statuses) != 0 {
+		t.Errorf("got %v statuses, the release is in the state", len(stub.statuses))
+	}
+	state, err := readState(stateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(state.Releases) != 1 || state.Releases[0].ID != 2 {
+		t.Errorf("got state %+v, want only the release posted within the days", state)
+	}
+}
+
+func TestStatusText(t *testing.T) {
+	tests := []struct {
+		name         string
+		announcement Announcement
+		limit        int
+		want         string
+	}{
+		{
+			name:         "tag only",
+			announcement: Announcement{Organization: "hyperledger", Repository: "fabric", Name: "v2.3.2", TagName: "v2.3.2", URL: "https://example.org/r"},
+			limit:        500,
+			want:         "🚀 hyperledger/fabric v2.3.2 is out!\n\nhttps://example.org/r",
+		},
+		{
+			name:         "no name",
+			announcement: Announcement{Organization: "hyperledger", Repository: "fabric", URL: "https://example.org/r"},
+			limit:        500,
+			want:         "🚀 hyperledger/fabric is out!\n\nhttps://example.org/r",
+		},
+		{
+			name:         "name left out when it does not fit",
+			announcement: Announcement{Organization: "hyperledger", Repository: "fabric", Name: "v2.3.2", URL: "https://example.org/r"},
+			limit:        50,
+			want:         "🚀 hyperledger/fabric is out!\n\nhttps://example.org/r",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := StatusText(test.announcement, nil, test.limit)
+			if got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
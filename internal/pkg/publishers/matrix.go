@@ -0,0 +1,93 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package publishers
+
+import (
+	"errors"
+	"fmt"
+	"github-updates/internal/pkg/configs"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// PresetMatrix sends the digest as an m.room.message through the
+// Matrix client-server API
+const PresetMatrix = "matrix"
+
+// presetTemplates are the body templates of the presets
+var presetTemplates = map[string]string{
+	PresetMatrix: matrixTemplate,
+}
+
+// matrixTemplate has the plain text body along with the html
+// formatted body shown by most of the clients
+const matrixTemplate = `{{- $text := printf "%v\n" .Title -}}
+{{- $html := printf "<h3>%v</h3>" (html .Title) -}}
+{{- if .Releases -}}
+{{- $text = printf "%v\nNew releases\n" $text -}}
+{{- $html = printf "%v<p><strong>New releases</strong></p><ul>" $html -}}
+{{- range .Releases -}}
+{{- $text = printf "%v- %v/%v %v %v\n" $text .Organization .Repository .Name .URL -}}
+{{- $html = printf "%v<li><a href=\"%v\">%v/%v %v</a></li>" $html (html .URL) .Organization .Repository (html .Name) -}}
+{{- end -}}
+{{- $html = printf "%v</ul>" $html -}}
+{{- end -}}
+{{- if .MergedPRs -}}
+{{- $text = printf "%v\nMerged pull requests\n" $text -}}
+{{- $html = printf "%v<p><strong>Merged pull requests</strong></p><ul>" $html -}}
+{{- range .MergedPRs -}}
+{{- $text = printf "%v- %v/%v: %v merged\n" $text .Organization .Repository .Count -}}
+{{- $html = printf "%v<li>%v/%v: %v merged</li>" $html .Organization .Repository .Count -}}
+{{- end -}}
+{{- $html = printf "%v</ul>" $html -}}
+{{- end -}}
+{{- if .NewIssues -}}
+{{- $text = printf "%v\nNew good first issues\n" $text -}}
+{{- $html = printf "%v<p><strong>New good first issues</strong></p><ul>" $html -}}
+{{- range .NewIssues -}}
+{{- $text = printf "%v- %v/%v#%v %v %v\n" $text .Organization .Repository .Number .Title .URL -}}
+{{- $html = printf "%v<li><a href=\"%v\">%v/%v#%v</a> %v</li>" $html (html .URL) .Organization .Repository .Number (html .Title) -}}
+{{- end -}}
+{{- $html = printf "%v</ul>" $html -}}
+{{- end -}}
+{
+  "msgtype": "m.text",
+  "body": {{json $text}},
+  "format": "org.matrix.custom.html",
+  "formatted_body": {{json $html}}
+}`
+
+// matrixWebhook fills in the send endpoint of the room and the
+// access token auth of the preset
+func matrixWebhook(webhook configs.WebhookConfiguration) (configs.WebhookConfiguration, error) {
+	if webhook.Matrix.Homeserver == "" || webhook.Matrix.RoomID == "" {
+		return webhook, errors.New("matrix preset needs the homeserver and the room-id")
+	}
+	// the transaction id makes the retries idempotent
+	transactionID := fmt.Sprintf("github-updates-%v", time.Now().UnixNano())
+	webhook.URL = fmt.Sprintf("%v/_matrix/client/v3/rooms/%v/send/m.room.message/%v",
+		strings.TrimRight(webhook.Matrix.Homeserver, "/"),
+		url.PathEscape(webhook.Matrix.RoomID),
+		transactionID,
+	)
+	webhook.Method = "PUT"
+	if webhook.Auth.Type == "" {
+		webhook.Auth.Type = AuthBearer
+	}
+	return webhook, nil
+}
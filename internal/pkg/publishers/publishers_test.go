@@ -0,0 +1,297 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package publishers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github-updates/internal/pkg/configs"
+
+	"github.com/google/go-github/v33/github"
+)
+
+// receiver is a local stand-in for the webhooks, it keeps the
+// requests it was sent
+type receiver struct {
+	*httptest.Server
+	mutex    sync.Mutex
+	requests []*http.Request
+	bodies   [][]byte
+	// status is answered to the requests, 204 when it is not set
+	status int
+}
+
+func newReceiver(t *testing.T) *receiver {
+	r := &receiver{}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, request *http.Request) {
+		body, err := ioutil.ReadAll(request.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		r.mutex.Lock()
+		r.requests = append(r.requests, request)
+		r.bodies = append(r.bodies, body)
+		status := r.status
+		r.mutex.Unlock()
+		if status == 0 {
+			status = http.StatusNoContent
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+// decode reads the body of every request into the slice v points to
+func (r *receiver) decode(t *testing.T, v interface{}) {
+	t.Helper()
+	bodies := make([]json.RawMessage, len(r.bodies))
+	for index, body := range r.bodies {
+		bodies[index] = body
+	}
+	contents, err := json.Marshal(bodies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = json.Unmarshal(contents, v)
+	if err != nil {
+		t.Fatalf("%v: %s", err, contents)
+	}
+}
+
+func testDigest(issues int) Digest {
+	digest := Digest{
+		Releases: []DigestRelease{{
+			Organization: "hyperledger",
+			Repository:   "fabric",
+			Name:         "v2.3.2 <stable>",
+			TagName:      "v2.3.2",
+			URL:          "https://github.com/hyperledger/fabric/releases/tag/v2.3.2",
+		}},
+		MergedPRs: []ProjectCount{{Organization: "hyperledger", Repository: "besu", Count: 12}},
+	}
+	for number := 1; number <= issues; number++ {
+		digest.NewIssues = append(digest.NewIssues, DigestIssue{
+			Organization: "hyperledger",
+			Repository:   "fabric",
+			Number:       number,
+			Title:        fmt.Sprintf("Issue %v %v", number, strings.Repeat("x", 200)),
+			URL:          fmt.Sprintf("https://github.com/hyperledger/fabric/issues/%v", number),
+		})
+	}
+	return digest
+}
+
+func TestPublishSlack(t *testing.T) {
+	r := newReceiver(t)
+	err := PublishSlack(r.URL, "Weekly updates", testDigest(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var messages []slackMessage
+	r.decode(t, &messages)
+	if len(messages) != 1 {
+		t.Fatalf("got %v messages, want 1", len(messages))
+	}
+	if r.requests[0].Method != "POST" || r.requests[0].Header.Get("Content-Type") != "application/json" {
+		t.Errorf("got %v with %v", r.requests[0].Method, r.requests[0].Header.Get("Content-Type"))
+	}
+	message := messages[0]
+	if message.Text != "Weekly updates" || message.Blocks[0].Type != "header" {
+		t.Errorf("message does not start with the title: %+v", message)
+	}
+	var sections []string
+	for _, block := range message.Blocks {
+		if block.Type == "section" {
+			sections = append(sections, block.Text.Text)
+		}
+	}
+	want := []string{
+		"*New releases*\n• <https://github.com/hyperledger/fabric/releases/tag/v2.3.2|hyperledger/fabric v2.3.2 &lt;stable&gt;>",
+		"*Merged pull requests*\n• hyperledger/besu: 12 merged",
+	}
+	if len(sections) != 3 || sections[0] != want[0] || sections[1] != want[1] {
+		t.Errorf("got sections %q, want them to start with %q", sections, want)
+	}
+}
+
+func TestSlackChunks(t *testing.T) {
+	messages := slackMessages("Weekly updates", testDigest(1000))
+	if len(messages) < 2 {
+		t.Fatalf("got %v messages, want the digest split", len(messages))
+	}
+	issues := 0
+	for _, message := range messages {
+		if len(message.Blocks) > slackMaxBlocks {
+			t.Errorf("message has %v blocks", len(message.Blocks))
+		}
+		if message.Blocks[0].Type != "header" {
+			t.Errorf("message does not start with the header")
+		}
+		size := 0
+		for _, block := range message.Blocks[1:] {
+			if block.Text == nil {
+				continue
+			}
+			text := []rune(block.Text.Text)
+			if len(text) > slackMaxSectionText {
+				t.Errorf("section has %v characters", len(text))
+			}
+			size += len(text)
+			issues += strings.Count(block.Text.Text, "/issues/")
+		}
+		if size > slackMaxMessageText {
+			t.Errorf("message has %v characters", size)
+		}
+	}
+	if issues != 1000 {
+		t.Errorf("got %v issues, want all 1000", issues)
+	}
+}
+
+func TestPublishDiscord(t *testing.T) {
+	r := newReceiver(t)
+	err := PublishDiscord(r.URL, "Weekly updates", testDigest(200))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var messages []discordMessage
+	r.decode(t, &messages)
+	if len(messages) < 2 {
+		t.Fatalf("got %v messages, want the digest split", len(messages))
+	}
+	if messages[0].Content != "Weekly updates" || messages[1].Content != "" {
+		t.Errorf("the title is not only in the first message: %q, %q", messages[0].Content, messages[1].Content)
+	}
+	first := messages[0].Embeds[0]
+	if first.Title != "New releases" || first.Color != discordColor ||
+		first.Description != "• [hyperledger/fabric v2.3.2 <stable>](https://github.com/hyperledger/fabric/releases/tag/v2.3.2)" {
+		t.Errorf("got release embed %+v", first)
+	}
+	issues := 0
+	for _, message := range messages {
+		if len(message.Embeds) > discordMaxEmbeds {
+			t.Errorf("message has %v embeds", len(message.Embeds))
+		}
+		size := 0
+		for _, embed := range message.Embeds {
+			description := []rune(embed.Description)
+			if len(description) > discordMaxDescription {
+				t.Errorf("embed has %v characters", len(description))
+			}
+			size += len([]rune(embed.Title)) + len(description)
+			issues += strings.Count(embed.Description, "/issues/")
+		}
+		if size > discordMaxMessageText {
+			t.Errorf("message has %v characters", size)
+		}
+	}
+	if issues != 200 {
+		t.Errorf("got %v issues, want all 200", issues)
+	}
+}
+
+func TestPublishWebhook(t *testing.T) {
+	t.Setenv("TEST_WEBHOOK_TOKEN", "secret")
+	r := newReceiver(t)
+	webhook := configs.WebhookConfiguration{
+		Name:    "chat-ops",
+		URL:     r.URL + "/hooks/updates",
+		Method:  "PUT",
+		Headers: map[string]string{"X-Source": "github-updates"},
+		Auth:    configs.WebhookAuth{Type: AuthBearer, SecretEnv: "TEST_WEBHOOK_TOKEN"},
+	}
+	webhook.Template = writeTemplate(t, `{"title": {{json .Title}}, "releases": [{{range $index, $release := .Releases}}{{if $index}}, {{end}}{{json $release.Name}}{{end}}], "issues": {{len .NewIssues}}}`)
+	err := PublishWebhook(webhook, "Weekly \"updates\"", testDigest(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.requests) != 1 {
+		t.Fatalf("got %v requests, want 1", len(r.requests))
+	}
+	request := r.requests[0]
+	if request.Method != "PUT" || request.URL.Path != "/hooks/updates" {
+		t.Errorf("got %v %v", request.Method, request.URL.Path)
+	}
+	if request.Header.Get("Authorization") != "Bearer secret" || request.Header.Get("X-Source") != "github-updates" {
+		t.Errorf("got headers %v", request.Header)
+	}
+	var bodies []map[string]interface{}
+	r.decode(t, &bodies)
+	want := map[string]interface{}{
+		"title":    "Weekly \"updates\"",
+		"releases": []interface{}{"v2.3.2 <stable>"},
+		"issues":   float64(3),
+	}
+	if fmt.Sprint(bodies[0]) != fmt.Sprint(want) {
+		t.Errorf("got body %v, want %v", bodies[0], want)
+	}
+}
+
+func TestPublishWebhookError(t *testing.T) {
+	r := newReceiver(t)
+	r.status = http.StatusBadRequest
+	err := PublishSlack(r.URL, "Weekly updates", testDigest(1))
+	if err == nil || !strings.Contains(err.Error(), "400") {
+		t.Errorf("got %v, want the status of the receiver", err)
+	}
+}
+
+func writeTemplate(t *testing.T, text string) string {
+	file := t.TempDir() + "/body.tmpl"
+	err := ioutil.WriteFile(file, []byte(text), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestNewDigestLatestIssues(t *testing.T) {
+	since := time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC)
+	issue := func(number int, created time.Time) github.Issue {
+		return github.Issue{Number: github.Int(number), CreatedAt: &created}
+	}
+	issues := []configs.IssueDetails{{
+		Organization: "hyperledger",
+		IssueLists: []configs.IssueList{
+			{Repository: "fabric", Issues: []github.Issue{
+				issue(1, since.AddDate(0, 0, 1)),
+				issue(2, since.AddDate(0, 0, -1)),
+			}},
+			{Repository: "besu", Issues: []github.Issue{
+				issue(3, since.AddDate(0, 0, 3)),
+				issue(4, since.AddDate(0, 0, 2)),
+			}},
+		},
+	}}
+	digest := NewDigest(configs.DigestConfiguration{TopIssues: 2}, since, nil, nil, issues)
+	var numbers []int
+	for _, newIssue := range digest.NewIssues {
+		numbers = append(numbers, newIssue.Number)
+	}
+	if fmt.Sprint(numbers) != "[3 4]" {
+		t.Errorf("got issues %v, want the latest two [3 4]", numbers)
+	}
+}
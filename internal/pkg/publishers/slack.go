@@ -0,0 +1,124 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package publishers
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Slack limits, see https://api.slack.com/reference/block-kit/blocks
+const (
+	slackMaxBlocks      = 50
+	slackMaxSectionText = 3000
+	slackMaxHeaderText  = 150
+	// longer messages are truncated by Slack
+	slackMaxMessageText = 40000
+)
+
+type slackMessage struct {
+	Text   string       `json:"text"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+type slackBlock struct {
+	Type string     `json:"type"`
+	Text *slackText `json:"text,omitempty"`
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// slackMessages lays the digest out in Block Kit, the sections are
+// split to keep within the text and the block limits of a message
+func slackMessages(title string, digest Digest) []slackMessage {
+	var blocks []slackBlock
+	addSection := func(heading string, lines []string) {
+		if len(lines) == 0 {
+			return
+		}
+		blocks = append(blocks, slackBlock{Type: "divider"})
+		lines = append([]string{"*" + heading + "*"}, lines...)
+		for _, chunk := range chunkLines(lines, slackMaxSectionText) {
+			blocks = append(blocks, slackBlock{
+				Type: "section",
+				Text: &slackText{Type: "mrkdwn", Text: chunk},
+			})
+		}
+	}
+
+	var lines []string
+	for _, release := range digest.Releases {
+		lines = append(lines, fmt.Sprintf("• <%v|%v/%v %v>",
+			release.URL, release.Organization, release.Repository, slackEscaper.Replace(release.Name)))
+	}
+	addSection("New releases", lines)
+
+	lines = nil
+	for _, project := range digest.MergedPRs {
+		lines = append(lines, fmt.Sprintf("• %v/%v: %v merged", project.Organization, project.Repository, project.Count))
+	}
+	addSection("Merged pull requests", lines)
+
+	lines = nil
+	for _, issue := range digest.NewIssues {
+		lines = append(lines, fmt.Sprintf("• <%v|%v/%v#%v> %v",
+			issue.URL, issue.Organization, issue.Repository, issue.Number, slackEscaper.Replace(issue.Title)))
+	}
+	addSection("New good first issues", lines)
+
+	header := slackBlock{
+		Type: "header",
+		Text: &slackText{Type: "plain_text", Text: truncate(title, slackMaxHeaderText)},
+	}
+	var messages []slackMessage
+	current := slackMessage{Text: title, Blocks: []slackBlock{header}}
+	size := 0
+	for _, block := range blocks {
+		blockSize := 0
+		if block.Text != nil {
+			blockSize = utf8.RuneCountInString(block.Text.Text)
+		}
+		if len(current.Blocks) == slackMaxBlocks || size+blockSize > slackMaxMessageText {
+			messages = append(messages, current)
+			current = slackMessage{Text: title, Blocks: []slackBlock{header}}
+			size = 0
+		}
+		current.Blocks = append(current.Blocks, block)
+		size += blockSize
+	}
+	if len(current.Blocks) > 1 {
+		messages = append(messages, current)
+	}
+	return messages
+}
+
+// PublishSlack posts the digest to the Slack incoming webhook
+func PublishSlack(webhookURL string, title string, digest Digest) error {
+	for _, message := range slackMessages(title, digest) {
+		_, err := postJSON("POST", webhookURL, nil, message)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
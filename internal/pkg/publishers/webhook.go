@@ -0,0 +1,121 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package publishers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxRetries is the number of times a rate limited post is retried
+const maxRetries = 3
+
+// HTTPClient is used for all the posts, replaced to point at a
+// local receiver
+var HTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// postJSON sends the payload to the url, it waits and retries when
+// the receiver rate limits the posts
+func postJSON(method string, url string, headers map[string]string, payload interface{}) ([]byte, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return post(method, url, headers, body)
+}
+
+func post(method string, url string, headers map[string]string, body []byte) ([]byte, error) {
+	for attempt := 0; ; attempt++ {
+		request, err := http.NewRequest(method, url, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		request.Header.Set("Content-Type", "application/json")
+		for key, value := range headers {
+			request.Header.Set(key, value)
+		}
+		response, err := HTTPClient.Do(request)
+		if err != nil {
+			return nil, err
+		}
+		contents, err := ioutil.ReadAll(io.LimitReader(response.Body, 1<<20))
+		response.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if response.StatusCode == http.StatusTooManyRequests && attempt < maxRetries {
+			wait := retryAfter(response.Header.Get("Retry-After"))
+			log.Printf("Rate limited by the webhook, retrying in %v", wait)
+			time.Sleep(wait)
+			continue
+		}
+		if response.StatusCode < 200 || response.StatusCode >= 300 {
+			return nil, fmt.Errorf("webhook responded %v: %v", response.Status, strings.TrimSpace(string(contents)))
+		}
+		return contents, nil
+	}
+}
+
+// retryAfter reads the wait time in seconds, it may be fractional
+func retryAfter(value string) time.Duration {
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil || seconds <= 0 {
+		return time.Second
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// truncate shortens the text to the number of characters, marking
+// the cut with an ellipsis
+func truncate(text string, limit int) string {
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	return string([]rune(text)[:limit-1]) + "…"
+}
+
+// chunkLines joins the lines into chunks of at most limit
+// characters, a line longer than the limit is truncated
+func chunkLines(lines []string, limit int) []string {
+	var chunks []string
+	current := ""
+	for _, line := range lines {
+		line = truncate(line, limit)
+		if current != "" && utf8.RuneCountInString(current)+1+utf8.RuneCountInString(line) > limit {
+			chunks = append(chunks, current)
+			current = ""
+		}
+		if current == "" {
+			current = line
+		} else {
+			current += "\n" + line
+		}
+	}
+	if current != "" {
+		chunks = append(chunks, current)
+	}
+	return chunks
+}
@@ -0,0 +1,125 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package ranking scores the items of the reports by their
+// engagement, so the summary can show the items people care about
+// rather than the newest ones
+package ranking
+
+import (
+	"github-updates/internal/pkg/configs"
+	"math"
+)
+
+// DefaultWeights are used when no weight is configured
+var DefaultWeights = configs.RankingWeights{
+	Reactions:     1,
+	Comments:      1,
+	Reviews:       2,
+	Stars:         1,
+	AuthorNovelty: 3,
+}
+
+// Signals are the raw activity values of an item
+type Signals struct {
+	Reactions int
+	Comments  int
+	Reviews   int
+	Stars     int
+	// Author is the login of the author, novelty is computed
+	// across all the ranked items
+	Author string
+	// NewContributor tells if the author is a new contributor, it is
+	// nil when the new contributors were not looked up
+	NewContributor *bool
+}
+
+// Component is one signal of a score, Points is Value times Weight
+type Component struct {
+	Value  float64 `json:"value"`
+	Weight float64 `json:"weight"`
+	Points float64 `json:"points"`
+}
+
+// Score is the total of an item with the breakdown of every signal
+type Score struct {
+	Total         float64   `json:"total"`
+	Reactions     Component `json:"reactions"`
+	Comments      Component `json:"comments"`
+	Reviews       Component `json:"reviews"`
+	Stars         Component `json:"stars"`
+	AuthorNovelty Component `json:"authorNovelty"`
+}
+
+// Scores ranks the items together. The counts are damped with the
+// logarithm so a single busy item or a very popular repository does
+// not dominate. The author novelty is 1 for a new contributor and 0
+// for the others, when that is not known it is 1 for an author of a
+// single item and shrinks with every other item of the same author.
+func Scores(signals []Signals, weights configs.RankingWeights) []Score {
+	if weights == (configs.RankingWeights{}) {
+		weights = DefaultWeights
+	}
+	authored := map[string]int{}
+	for _, signal := range signals {
+		if signal.Author != "" {
+			authored[signal.Author]++
+		}
+	}
+	scores := make([]Score, 0, len(signals))
+	for _, signal := range signals {
+		novelty := 0.0
+		if signal.NewContributor != nil {
+			if *signal.NewContributor {
+				novelty = 1
+			}
+		} else if count := authored[signal.Author]; count > 0 {
+			novelty = 1 / float64(count)
+		}
+		score := Score{
+			Reactions:     component(damp(signal.Reactions), weights.Reactions),
+			Comments:      component(damp(signal.Comments), weights.Comments),
+			Reviews:       component(damp(signal.Reviews), weights.Reviews),
+			Stars:         component(damp(signal.Stars), weights.Stars),
+			AuthorNovelty: component(novelty, weights.AuthorNovelty),
+		}
+		score.Total = round(score.Reactions.Points + score.Comments.Points +
+			score.Reviews.Points + score.Stars.Points + score.AuthorNovelty.Points)
+		scores = append(scores, score)
+	}
+	return scores
+}
+
+func component(value float64, weight float64) Component {
+	return Component{
+		Value:  round(value),
+		Weight: weight,
+		Points: round(value * weight),
+	}
+}
+
+// damp maps the counts onto a logarithmic scale, 0 stays 0
+func damp(count int) float64 {
+	if count <= 0 {
+		return 0
+	}
+	return math.Log2(float64(count) + 1)
+}
+
+// round keeps two decimals, enough to read the breakdown
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,42 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ranking
+
+import (
+	"testing"
+
+	"github-updates/internal/pkg/configs"
+)
+
+func TestAuthorNovelty(t *testing.T) {
+	isNew, isKnown := true, false
+	weights := configs.RankingWeights{AuthorNovelty: 1}
+	scores := Scores([]Signals{
+		{Author: "newcomer", NewContributor: &isNew},
+		{Author: "newcomer", NewContributor: &isNew},
+		{Author: "maintainer", NewContributor: &isKnown},
+		// not looked up, shared between the items of the author
+		{Author: "someone"},
+		{Author: "someone"},
+	}, weights)
+	want := []float64{1, 1, 0, 0.5, 0.5}
+	for index, score := range scores {
+		if score.AuthorNovelty.Value != want[index] {
+			t.Errorf("item %v has a novelty of %v, want %v", index, score.AuthorNovelty.Value, want[index])
+		}
+	}
+}
@@ -0,0 +1,664 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package schema has the versioned layout of the data files. Only
+// the fields used by the reports are kept, so that the files stay
+// the same across the go-github versions. The layout is published
+// as a JSON Schema in assets/schema, any change to it must bump
+// the Version.
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"github-updates/internal/pkg/configs"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v33/github"
+)
+
+// Version of the data file layout
+const Version = "1.15"
+
+// Document is the content of a data file
+type Document struct {
+	SchemaVersion string         `json:"schemaVersion"`
+	Kind          string         `json:"kind"`
+	GeneratedAt   time.Time      `json:"generatedAt"`
+	Organizations []Organization `json:"organizations"`
+}
+
+// Organization groups the repositories of a GitHub organization
+type Organization struct {
+	Name            string           `json:"name"`
+	Repositories    []Repository     `json:"repositories"`
+	NewContributors []NewContributor `json:"newContributors,omitempty"`
+	Contributors    []Contributor    `json:"contributors,omitempty"`
+	Comparison      *configs.Delta   `json:"comparison,omitempty"`
+	// ListedRepositories are the names of all the repositories of the
+	// organization when the data was collected, pull request, release
+	// and issue reports only
+	ListedRepositories []string `json:"listedRepositories,omitempty"`
+}
+
+// Contributor has the activity counts of an author
+type Contributor struct {
+	Author       User `json:"author"`
+	PRsOpened    int  `json:"prsOpened"`
+	PRsMerged    int  `json:"prsMerged"`
+	ReviewsGiven int  `json:"reviewsGiven"`
+	IssuesOpened int  `json:"issuesOpened"`
+}
+
+// NewContributor is the author of a first PR in the repository or
+// the organization, with the PR
+type NewContributor struct {
+	Author      User      `json:"author"`
+	Repository  string    `json:"repository"`
+	PullRequest Reference `json:"pullRequest"`
+	Association string    `json:"association,omitempty"`
+}
+
+// Reference points to an item
+type Reference struct {
+	Number    int        `json:"number"`
+	Title     string     `json:"title"`
+	URL       string     `json:"url"`
+	Merged    bool       `json:"merged"`
+	CreatedAt *time.Time `json:"createdAt,omitempty"`
+}
+
+// Repository has the items collected for a repository
+type Repository struct {
+	// ID of the repository on GitHub, pull request, release and issue
+	// reports only
+	ID     int64    `json:"id,omitempty"`
+	Name   string   `json:"name"`
+	URL    string   `json:"url"`
+	Stars  int      `json:"stars,omitempty"`
+	Labels []string `json:"labels,omitempty"`
+	Items  []Item   `json:"items"`
+	// Contributors of the repository, contributor reports only
+	Contributors []Contributor `json:"contributors,omitempty"`
+	// CycleTime of the pull requests of the repository, when enabled
+	CycleTime *configs.CycleTimeSummary `json:"cycleTime,omitempty"`
+	// Health of the repository, scorecard reports only
+	Health *Health `json:"health,omitempty"`
+	// Timeline of the releases, release timeline reports only
+	Timeline *Timeline `json:"timeline,omitempty"`
+	// Commits has the totals of the commits, commit reports only
+	Commits *CommitTotals `json:"commits,omitempty"`
+	// Milestones open in the repository, milestone reports only
+	Milestones []configs.Milestone `json:"milestones,omitempty"`
+}
+
+// CommitTotals of the commits of a repository, the files and the
+// lines are only counted when the files are fetched
+type CommitTotals struct {
+	Branch       string `json:"branch"`
+	Commits      int    `json:"commits"`
+	Authors      int    `json:"authors"`
+	FilesChanged int    `json:"filesChanged"`
+	Additions    int    `json:"additions"`
+	Deletions    int    `json:"deletions"`
+}
+
+// Timeline is the release cadence of a repository with the series
+// of its releases, the oldest first
+type Timeline struct {
+	Releases           int                     `json:"releases"`
+	FirstRelease       *time.Time              `json:"firstRelease,omitempty"`
+	LastRelease        *time.Time              `json:"lastRelease,omitempty"`
+	AverageDaysBetween *float64                `json:"averageDaysBetween,omitempty"`
+	DaysSinceLast      *int                    `json:"daysSinceLast,omitempty"`
+	SLADays            int                     `json:"slaDays,omitempty"`
+	OverSLA            bool                    `json:"overSla"`
+	Series             []configs.TimelinePoint `json:"series"`
+}
+
+// Health is the scorecard of a repository
+type Health struct {
+	Signals configs.HealthSignals `json:"signals"`
+	Score   configs.HealthScore   `json:"score"`
+}
+
+// Item is a pull request, an issue or a release
+type Item struct {
+	// ID and NodeID of the pull requests, issues and releases, the
+	// other items are known by their SHA, GHSAID or URL
+	ID          int64      `json:"id,omitempty"`
+	NodeID      string     `json:"nodeId,omitempty"`
+	Number      int        `json:"number,omitempty"`
+	Title       string     `json:"title"`
+	URL         string     `json:"url"`
+	Body        string     `json:"body,omitempty"`
+	Author      User       `json:"author"`
+	State       string     `json:"state,omitempty"`
+	Labels      []string   `json:"labels,omitempty"`
+	Comments    int        `json:"comments,omitempty"`
+	TagName     string     `json:"tagName,omitempty"`
+	Prerelease  bool       `json:"prerelease,omitempty"`
+	CreatedAt   *time.Time `json:"createdAt,omitempty"`
+	UpdatedAt   *time.Time `json:"updatedAt,omitempty"`
+	MergedAt    *time.Time `json:"mergedAt,omitempty"`
+	ClosedAt    *time.Time `json:"closedAt,omitempty"`
+	PublishedAt *time.Time `json:"publishedAt,omitempty"`
+	// CycleTime of the pull request, when enabled
+	CycleTime *configs.CycleTime `json:"cycleTime,omitempty"`
+	// Reason the item is stuck and the days it has been idle,
+	// stale reports only
+	Reason   string `json:"reason,omitempty"`
+	IdleDays int    `json:"idleDays,omitempty"`
+	// SHA and the co-authors of the commit, commit reports only
+	SHA       string `json:"sha,omitempty"`
+	CoAuthors []User `json:"coAuthors,omitempty"`
+	// Category, upvotes and the chosen answer of the discussion,
+	// discussion reports only
+	Category   string     `json:"category,omitempty"`
+	Upvotes    int        `json:"upvotes,omitempty"`
+	AnsweredAt *time.Time `json:"answeredAt,omitempty"`
+	AnswerURL  string     `json:"answerUrl,omitempty"`
+	// Type of the entry, the advisory it is or refers to, its
+	// severity and the versions, security reports only
+	Type             string   `json:"type,omitempty"`
+	GHSAID           string   `json:"ghsaId,omitempty"`
+	CVEID            string   `json:"cveId,omitempty"`
+	References       []string `json:"references,omitempty"`
+	Severity         string   `json:"severity,omitempty"`
+	AffectedVersions string   `json:"affectedVersions,omitempty"`
+	PatchedVersions  string   `json:"patchedVersions,omitempty"`
+}
+
+// Types of the items of the security reports
+const (
+	TypeAdvisory    = "advisory"
+	TypeRelease     = "release"
+	TypePullRequest = "pull-request"
+)
+
+// Reasons of the items of the stale reports
+const (
+	ReasonInactive   = "inactive"
+	ReasonUnreviewed = "unreviewed"
+	ReasonAbandoned  = "abandoned"
+)
+
+// User is the author of an item
+type User struct {
+	Login     string `json:"login"`
+	Name      string `json:"name,omitempty"`
+	URL       string `json:"url,omitempty"`
+	AvatarURL string `json:"avatarUrl,omitempty"`
+}
+
+// New converts the report data of the kind into the document
+func New(kind string, v interface{}) (Document, error) {
+	document := Document{
+		SchemaVersion: Version,
+		Kind:          kind,
+		GeneratedAt:   time.Now().UTC(),
+		Organizations: []Organization{},
+	}
+	switch details := v.(type) {
+	case []configs.PullRequestDetails:
+		for _, org := range details {
+			organization := Organization{
+				Name:               org.Organization,
+				Repositories:       []Repository{},
+				Comparison:         org.Comparison,
+				ListedRepositories: org.ListedRepositories,
+			}
+			for _, repo := range org.PrRepoLists {
+				repository := newRepository(org.Organization, repo.Repository)
+				repository.ID = repo.RepositoryID
+				repository.Stars = repo.Stars
+				repository.CycleTime = repo.CycleTimeSummary
+				for _, pr := range repo.PRs {
+					item := PullRequestItem(pr)
+					if cycleTime, found := repo.CycleTime[pr.GetNumber()]; found {
+						item.CycleTime = &cycleTime
+					}
+					repository.Items = append(repository.Items, item)
+				}
+				organization.Repositories = append(organization.Repositories, repository)
+			}
+			for _, contributor := range org.NewContributors {
+				organization.NewContributors =
+					append(organization.NewContributors, newContributor(contributor))
+			}
+			document.Organizations = append(document.Organizations, organization)
+		}
+	case []configs.ReleaseDetails:
+		for _, org := range details {
+			organization := Organization{
+				Name:               org.Organization,
+				Repositories:       []Repository{},
+				Comparison:         org.Comparison,
+				ListedRepositories: org.ListedRepositories,
+			}
+			for _, repo := range org.ReleaseRepoLists {
+				repository := newRepository(org.Organization, repo.Repository)
+				repository.ID = repo.RepositoryID
+				repository.Stars = repo.Stars
+				for _, release := range repo.Releases {
+					repository.Items = append(repository.Items, ReleaseItem(release))
+				}
+				organization.Repositories = append(organization.Repositories, repository)
+			}
+			document.Organizations = append(document.Organizations, organization)
+		}
+	case []configs.IssueDetails:
+		for _, org := range details {
+			organization := Organization{
+				Name:               org.Organization,
+				Repositories:       []Repository{},
+				Comparison:         org.Comparison,
+				ListedRepositories: org.ListedRepositories,
+			}
+			for _, repo := range org.IssueLists {
+				repository := newRepository(org.Organization, repo.Repository)
+				repository.ID = repo.RepositoryID
+				repository.Stars = repo.Stars
+				repository.Labels = repo.Labels
+				for _, issue := range repo.Issues {
+					repository.Items = append(repository.Items, IssueItem(issue))
+				}
+				organization.Repositories = append(organization.Repositories, repository)
+			}
+			document.Organizations = append(document.Organizations, organization)
+		}
+	case []configs.ContributorDetails:
+		for _, org := range details {
+			organization := Organization{
+				Name:         org.Organization,
+				Repositories: []Repository{},
+				Contributors: newContributors(org.Contributors),
+			}
+			for _, repo := range org.ContributorLists {
+				repository := newRepository(org.Organization, repo.Repository)
+				repository.Contributors = newContributors(repo.Contributors)
+				organization.Repositories = append(organization.Repositories, repository)
+			}
+			document.Organizations = append(document.Organizations, organization)
+		}
+	case []configs.StaleDetails:
+		for _, org := range details {
+			organization := Organization{Name: org.Organization, Repositories: []Repository{}}
+			for _, repo := range org.StaleLists {
+				repository := newRepository(org.Organization, repo.Repository)
+				for _, pr := range repo.InactivePRs {
+					repository.Items = append(repository.Items, staleItem(PullRequestItem(pr.PR), ReasonInactive, pr.IdleDays))
+				}
+				for _, pr := range repo.UnreviewedPRs {
+					repository.Items = append(repository.Items, staleItem(PullRequestItem(pr.PR), ReasonUnreviewed, pr.IdleDays))
+				}
+				for _, issue := range repo.AbandonedIssues {
+					repository.Items = append(repository.Items, staleItem(IssueItem(issue.Issue), ReasonAbandoned, issue.IdleDays))
+				}
+				organization.Repositories = append(organization.Repositories, repository)
+			}
+			document.Organizations = append(document.Organizations, organization)
+		}
+	case []configs.HealthDetails:
+		for _, org := range details {
+			organization := Organization{Name: org.Organization, Repositories: []Repository{}}
+			for _, repo := range org.Repositories {
+				repository := newRepository(org.Organization, repo.Repository)
+				repository.Health = &Health{Signals: repo.Signals, Score: repo.Score}
+				organization.Repositories = append(organization.Repositories, repository)
+			}
+			document.Organizations = append(document.Organizations, organization)
+		}
+	case []configs.TimelineDetails:
+		for _, org := range details {
+			organization := Organization{Name: org.Organization, Repositories: []Repository{}}
+			for _, repo := range org.Repositories {
+				repository := newRepository(org.Organization, repo.Repository)
+				repository.Timeline = &Timeline{
+					Releases:           repo.Releases,
+					FirstRelease:       repo.FirstRelease,
+					LastRelease:        repo.LastRelease,
+					AverageDaysBetween: repo.AverageDaysBetween,
+					DaysSinceLast:      repo.DaysSinceLast,
+					SLADays:            org.SLADays,
+					OverSLA:            repo.OverSLA,
+					Series:             repo.Series,
+				}
+				organization.Repositories = append(organization.Repositories, repository)
+			}
+			document.Organizations = append(document.Organizations, organization)
+		}
+	case []configs.CommitDetails:
+		for _, org := range details {
+			organization := Organization{Name: org.Organization, Repositories: []Repository{}}
+			for _, repo := range org.CommitLists {
+				repository := newRepository(org.Organization, repo.Repository)
+				repository.Commits = &CommitTotals{
+					Branch:       repo.Branch,
+					Commits:      len(repo.Commits),
+					Authors:      repo.Authors,
+					FilesChanged: repo.FilesChanged,
+					Additions:    repo.Additions,
+					Deletions:    repo.Deletions,
+				}
+				for _, commit := range repo.Commits {
+					repository.Items = append(repository.Items, CommitItem(commit))
+				}
+				organization.Repositories = append(organization.Repositories, repository)
+			}
+			document.Organizations = append(document.Organizations, organization)
+		}
+	case []configs.DiscussionDetails:
+		for _, org := range details {
+			organization := Organization{Name: org.Organization, Repositories: []Repository{}}
+			for _, repo := range org.DiscussionLists {
+				repository := newRepository(org.Organization, repo.Repository)
+				// a discussion started and answered is listed once
+				listed := map[int]bool{}
+				for _, category := range repo.Categories {
+					for _, discussion := range append(category.New, category.Answered...) {
+						if !listed[discussion.Number] {
+							listed[discussion.Number] = true
+							repository.Items = append(repository.Items, DiscussionItem(discussion))
+						}
+					}
+				}
+				organization.Repositories = append(organization.Repositories, repository)
+			}
+			document.Organizations = append(document.Organizations, organization)
+		}
+	case []configs.SecurityDetails:
+		for _, org := range details {
+			organization := Organization{Name: org.Organization, Repositories: []Repository{}}
+			for _, repo := range org.SecurityLists {
+				repository := newRepository(org.Organization, repo.Repository)
+				for _, advisory := range repo.Advisories {
+					repository.Items = append(repository.Items, AdvisoryItem(advisory))
+				}
+				for _, fix := range repo.Releases {
+					repository.Items = append(repository.Items, securityFixItem(fix, TypeRelease))
+				}
+				for _, fix := range repo.PRs {
+					repository.Items = append(repository.Items, securityFixItem(fix, TypePullRequest))
+				}
+				organization.Repositories = append(organization.Repositories, repository)
+			}
+			document.Organizations = append(document.Organizations, organization)
+		}
+	case []configs.MilestoneDetails:
+		for _, org := range details {
+			organization := Organization{Name: org.Organization, Repositories: []Repository{}}
+			for _, repo := range org.MilestoneLists {
+				repository := newRepository(org.Organization, repo.Repository)
+				repository.Milestones = repo.Milestones
+				organization.Repositories = append(organization.Repositories, repository)
+			}
+			document.Organizations = append(document.Organizations, organization)
+		}
+	default:
+		return Document{}, fmt.Errorf("no data schema for %T", v)
+	}
+	return document, nil
+}
+
+// Read loads a data file written with a 1.x version of the layout
+func Read(fileName string) (Document, error) {
+	fileContents, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return Document{}, err
+	}
+	var document Document
+	err = json.Unmarshal(fileContents, &document)
+	if err != nil {
+		return Document{}, err
+	}
+	if !strings.HasPrefix(document.SchemaVersion, "1.") {
+		return Document{}, fmt.Errorf("unsupported data file version %q in %v", document.SchemaVersion, fileName)
+	}
+	return document, nil
+}
+
+func newRepository(org string, repo string) Repository {
+	return Repository{
+		Name:  repo,
+		URL:   "https://github.com/" + org + "/" + repo,
+		Items: []Item{},
+	}
+}
+
+// PullRequestItem keeps the used fields of the pull request
+func PullRequestItem(pr github.PullRequest) Item {
+	item := Item{
+		ID:        pr.GetID(),
+		NodeID:    pr.GetNodeID(),
+		Number:    pr.GetNumber(),
+		Title:     pr.GetTitle(),
+		URL:       pr.GetHTMLURL(),
+		Body:      pr.GetBody(),
+		Author:    newUser(pr.GetUser()),
+		State:     pr.GetState(),
+		Comments:  pr.GetComments(),
+		CreatedAt: pr.CreatedAt,
+		UpdatedAt: pr.UpdatedAt,
+		MergedAt:  pr.MergedAt,
+		ClosedAt:  pr.ClosedAt,
+	}
+	if pr.MergedAt != nil {
+		item.State = "merged"
+	}
+	for _, label := range pr.Labels {
+		item.Labels = append(item.Labels, label.GetName())
+	}
+	return item
+}
+
+// IssueItem keeps the used fields of the issue
+func IssueItem(issue github.Issue) Item {
+	item := Item{
+		ID:        issue.GetID(),
+		NodeID:    issue.GetNodeID(),
+		Number:    issue.GetNumber(),
+		Title:     issue.GetTitle(),
+		URL:       issue.GetHTMLURL(),
+		Body:      issue.GetBody(),
+		Author:    newUser(issue.GetUser()),
+		State:     issue.GetState(),
+		Comments:  issue.GetComments(),
+		CreatedAt: issue.CreatedAt,
+		UpdatedAt: issue.UpdatedAt,
+		ClosedAt:  issue.ClosedAt,
+	}
+	for _, label := range issue.Labels {
+		item.Labels = append(item.Labels, label.GetName())
+	}
+	return item
+}
+
+// ReleaseItem keeps the used fields of the release
+func ReleaseItem(release github.RepositoryRelease) Item {
+	item := Item{
+		ID:         release.GetID(),
+		NodeID:     release.GetNodeID(),
+		Title:      release.GetName(),
+		URL:        release.GetHTMLURL(),
+		Body:       release.GetBody(),
+		Author:     newUser(release.GetAuthor()),
+		State:      "published",
+		TagName:    release.GetTagName(),
+		Prerelease: release.GetPrerelease(),
+	}
+	if item.Title == "" {
+		item.Title = release.GetTagName()
+	}
+	if release.CreatedAt != nil {
+		item.CreatedAt = &release.CreatedAt.Time
+	}
+	if release.PublishedAt != nil {
+		item.PublishedAt = &release.PublishedAt.Time
+	}
+	return item
+}
+
+func newContributor(contributor configs.NewContributor) NewContributor {
+	createdAt := contributor.CreatedAt
+	return NewContributor{
+		Author: User{
+			Login:     contributor.Login,
+			URL:       contributor.ProfileURL,
+			AvatarURL: contributor.AvatarURL,
+		},
+		Repository: contributor.Repository,
+		PullRequest: Reference{
+			Number:    contributor.Number,
+			Title:     contributor.Title,
+			URL:       contributor.URL,
+			Merged:    contributor.Merged,
+			CreatedAt: &createdAt,
+		},
+		Association: contributor.Association,
+	}
+}
+
+// CommitItem keeps the used fields of the commit, the subject is
+// the title and the date of the commit is the creation date
+func CommitItem(commit configs.Commit) Item {
+	date := commit.Date
+	item := Item{
+		Title:     commit.Subject,
+		URL:       commit.URL,
+		Author:    commitUser(commit.Author),
+		CreatedAt: &date,
+		SHA:       commit.SHA,
+	}
+	for _, coAuthor := range commit.CoAuthors {
+		item.CoAuthors = append(item.CoAuthors, commitUser(coAuthor))
+	}
+	return item
+}
+
+// DiscussionItem keeps the used fields of the discussion
+func DiscussionItem(discussion configs.Discussion) Item {
+	createdAt := discussion.CreatedAt
+	updatedAt := discussion.UpdatedAt
+	return Item{
+		Number: discussion.Number,
+		Title:  discussion.Title,
+		URL:    discussion.URL,
+		Author: User{
+			Login:     discussion.Author,
+			URL:       discussion.AuthorURL,
+			AvatarURL: discussion.AuthorAvatarURL,
+		},
+		Comments:   discussion.Comments,
+		CreatedAt:  &createdAt,
+		UpdatedAt:  &updatedAt,
+		Category:   discussion.Category,
+		Upvotes:    discussion.Upvotes,
+		AnsweredAt: discussion.AnswerChosenAt,
+		AnswerURL:  discussion.AnswerURL,
+	}
+}
+
+// AdvisoryItem keeps the used fields of the security advisory, the
+// versions of its packages are joined
+func AdvisoryItem(advisory configs.Advisory) Item {
+	publishedAt := advisory.PublishedAt
+	item := Item{
+		Title:       advisory.Summary,
+		URL:         advisory.URL,
+		PublishedAt: &publishedAt,
+		Type:        TypeAdvisory,
+		GHSAID:      advisory.GHSAID,
+		CVEID:       advisory.CVEID,
+		Severity:    advisory.Severity,
+	}
+	var affected, patched []string
+	for _, vulnerability := range advisory.Vulnerabilities {
+		if vulnerability.AffectedVersions != "" {
+			affected = append(affected, vulnerability.AffectedVersions)
+		}
+		if vulnerability.PatchedVersions != "" {
+			patched = append(patched, vulnerability.PatchedVersions)
+		}
+	}
+	item.AffectedVersions = strings.Join(affected, ", ")
+	item.PatchedVersions = strings.Join(patched, ", ")
+	return item
+}
+
+func securityFixItem(fix configs.SecurityFix, itemType string) Item {
+	item := Item{
+		Number:           fix.Number,
+		Title:            fix.Title,
+		URL:              fix.URL,
+		Author:           User{Login: fix.Author, URL: fix.AuthorURL},
+		TagName:          fix.TagName,
+		Type:             itemType,
+		References:       fix.References,
+		Severity:         fix.Severity,
+		AffectedVersions: fix.AffectedVersions,
+		PatchedVersions:  fix.PatchedVersions,
+	}
+	if itemType == TypeRelease {
+		item.PublishedAt = fix.Date
+	} else {
+		item.CreatedAt = fix.Date
+	}
+	return item
+}
+
+func commitUser(author configs.CommitAuthor) User {
+	return User{
+		Login:     author.Login,
+		Name:      author.Name,
+		URL:       author.ProfileURL,
+		AvatarURL: author.AvatarURL,
+	}
+}
+
+func staleItem(item Item, reason string, idleDays int) Item {
+	item.Reason = reason
+	item.IdleDays = idleDays
+	return item
+}
+
+func newContributors(statistics []configs.ContributorStats) []Contributor {
+	var contributors []Contributor
+	for _, stats := range statistics {
+		contributors = append(contributors, Contributor{
+			Author: User{
+				Login:     stats.Login,
+				URL:       stats.ProfileURL,
+				AvatarURL: stats.AvatarURL,
+			},
+			PRsOpened:    stats.PRsOpened,
+			PRsMerged:    stats.PRsMerged,
+			ReviewsGiven: stats.ReviewsGiven,
+			IssuesOpened: stats.IssuesOpened,
+		})
+	}
+	return contributors
+}
+
+func newUser(user *github.User) User {
+	return User{
+		Login:     user.GetLogin(),
+		URL:       user.GetHTMLURL(),
+		AvatarURL: user.GetAvatarURL(),
+	}
+}
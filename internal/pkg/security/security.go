@@ -0,0 +1,241 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package security gathers the security advisories and the fixes of
+// the vulnerabilities into a digest for the downstream users
+package security
+
+import (
+	"github-updates/internal/pkg/configs"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v33/github"
+)
+
+// DefaultLabels mark the PRs fixing a vulnerability when none are set
+var DefaultLabels = []string{"security"}
+
+// DefaultKeywords mark the PRs and the releases fixing a vulnerability
+// by their title when none are set
+var DefaultKeywords = []string{"security", "vulnerability", "cve"}
+
+// reference matches the CVE and the GHSA identifiers
+var reference = regexp.MustCompile(`(?i)\b(CVE-\d{4}-\d{4,}|GHSA(-[23456789cfghjmpqrvwx]{4}){3})\b`)
+
+// severities from the most severe
+var severities = map[string]int{
+	"critical": 0,
+	"high":     1,
+	"medium":   2,
+	"moderate": 2,
+	"low":      3,
+}
+
+// Digest adds the releases and the PRs fixing a vulnerability to the
+// advisories of every repository. A fix referring to an advisory of
+// the organization takes its severity and versions.
+func Digest(
+	advisoryLists []configs.SecurityList,
+	releaseLists []configs.ReleaseList,
+	prLists []configs.PrList,
+	config configs.SecurityConfiguration,
+) []configs.SecurityList {
+	labels := config.Labels
+	if len(labels) == 0 {
+		labels = DefaultLabels
+	}
+	keywords := config.Keywords
+	if len(keywords) == 0 {
+		keywords = DefaultKeywords
+	}
+
+	byRepository := map[string]*configs.SecurityList{}
+	var repositories []string
+	list := func(repo string) *configs.SecurityList {
+		if _, ok := byRepository[repo]; !ok {
+			byRepository[repo] = &configs.SecurityList{Repository: repo}
+			repositories = append(repositories, repo)
+		}
+		return byRepository[repo]
+	}
+	advisories := map[string]configs.Advisory{}
+	for _, advisoryList := range advisoryLists {
+		securityList := list(advisoryList.Repository)
+		securityList.Advisories = append(securityList.Advisories, advisoryList.Advisories...)
+		for _, advisory := range advisoryList.Advisories {
+			advisories[strings.ToUpper(advisory.GHSAID)] = advisory
+			if advisory.CVEID != "" {
+				advisories[strings.ToUpper(advisory.CVEID)] = advisory
+			}
+		}
+	}
+
+	for _, releaseList := range releaseLists {
+		for _, release := range releaseList.Releases {
+			references := References(release.GetName() + " " + release.GetBody())
+			if !hasKeyword(release.GetName(), keywords) && len(references) == 0 {
+				continue
+			}
+			publishedAt := release.GetPublishedAt().Time
+			fix := configs.SecurityFix{
+				Title:           release.GetName(),
+				URL:             release.GetHTMLURL(),
+				TagName:         release.GetTagName(),
+				Author:          release.GetAuthor().GetLogin(),
+				AuthorURL:       release.GetAuthor().GetHTMLURL(),
+				Date:            &publishedAt,
+				References:      references,
+				PatchedVersions: release.GetTagName(),
+			}
+			if fix.Title == "" {
+				fix.Title = fix.TagName
+			}
+			withAdvisory(&fix, references, advisories)
+			securityList := list(releaseList.Repository)
+			securityList.Releases = append(securityList.Releases, fix)
+		}
+	}
+
+	for _, prList := range prLists {
+		for _, pr := range prList.PRs {
+			references := References(pr.GetTitle() + " " + pr.GetBody())
+			if !hasLabel(pr, labels) && !hasKeyword(pr.GetTitle(), keywords) && len(references) == 0 {
+				continue
+			}
+			fix := configs.SecurityFix{
+				Title:      pr.GetTitle(),
+				URL:        pr.GetHTMLURL(),
+				Number:     pr.GetNumber(),
+				Author:     pr.GetUser().GetLogin(),
+				AuthorURL:  pr.GetUser().GetHTMLURL(),
+				Date:       pr.CreatedAt,
+				References: references,
+			}
+			withAdvisory(&fix, references, advisories)
+			securityList := list(prList.Repository)
+			securityList.PRs = append(securityList.PRs, fix)
+		}
+	}
+
+	var securityLists []configs.SecurityList
+	for _, repo := range repositories {
+		securityList := byRepository[repo]
+		sort.SliceStable(securityList.Advisories, func(i, j int) bool {
+			return severityRank(securityList.Advisories[i].Severity) < severityRank(securityList.Advisories[j].Severity)
+		})
+		securityLists = append(securityLists, *securityList)
+	}
+	return securityLists
+}
+
+// References are the distinct CVE and GHSA identifiers of the text,
+// in upper case
+func References(text string) []string {
+	var references []string
+	seen := map[string]bool{}
+	for _, match := range reference.FindAllString(text, -1) {
+		match = strings.ToUpper(match)
+		if !seen[match] {
+			seen[match] = true
+			references = append(references, match)
+		}
+	}
+	return references
+}
+
+// withAdvisory sets the severity and the versions of the first
+// advisory the fix refers to
+func withAdvisory(fix *configs.SecurityFix, references []string, advisories map[string]configs.Advisory) {
+	for _, id := range references {
+		advisory, ok := advisories[id]
+		if !ok {
+			continue
+		}
+		fix.Severity = advisory.Severity
+		var affected, patched []string
+		for _, vulnerability := range advisory.Vulnerabilities {
+			if vulnerability.AffectedVersions != "" {
+				affected = append(affected, vulnerability.AffectedVersions)
+			}
+			if vulnerability.PatchedVersions != "" {
+				patched = append(patched, vulnerability.PatchedVersions)
+			}
+		}
+		fix.AffectedVersions = strings.Join(affected, ", ")
+		if len(patched) != 0 {
+			fix.PatchedVersions = strings.Join(patched, ", ")
+		}
+		return
+	}
+}
+
+// hasKeyword tells if one of the keywords is a word of the title,
+// regardless of the case. "cve" matches "CVE-2021-1234" but not
+// "cvelib".
+func hasKeyword(title string, keywords []string) bool {
+	title = strings.ToLower(title)
+	for _, keyword := range keywords {
+		keyword = strings.ToLower(strings.TrimSpace(keyword))
+		if keyword == "" {
+			continue
+		}
+		for start := 0; ; {
+			index := strings.Index(title[start:], keyword)
+			if index < 0 {
+				break
+			}
+			index += start
+			end := index + len(keyword)
+			if !isWordByte(title, index-1) && !isWordByte(title, end) {
+				return true
+			}
+			start = index + 1
+		}
+	}
+	return false
+}
+
+// isWordByte tells if the byte at the index is a letter or a digit,
+// out of the text is not
+func isWordByte(text string, index int) bool {
+	if index < 0 || index >= len(text) {
+		return false
+	}
+	character := text[index]
+	return character >= 'a' && character <= 'z' || character >= '0' && character <= '9' ||
+		character >= 0x80
+}
+
+func hasLabel(pr github.PullRequest, labels []string) bool {
+	for _, label := range pr.Labels {
+		for _, name := range labels {
+			if strings.EqualFold(label.GetName(), name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// severityRank sorts the unknown severities last
+func severityRank(severity string) int {
+	if rank, ok := severities[strings.ToLower(severity)]; ok {
+		return rank
+	}
+	return len(severities)
+}
@@ -0,0 +1,134 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package security
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github-updates/internal/pkg/configs"
+
+	"github.com/google/go-github/v33/github"
+)
+
+func TestReferences(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{text: "Fix CVE-2021-44228 and cve-2021-44228 again", want: []string{"CVE-2021-44228"}},
+		{text: "See GHSA-jfh8-c2jp-5v3q, CVE-2021-1234", want: []string{"GHSA-JFH8-C2JP-5V3Q", "CVE-2021-1234"}},
+		// GHSA ids have no vowels, and a CVE id at least four digits
+		{text: "GHSA-abcd-efgh-ijkl CVE-2021-123", want: nil},
+		{text: "XCVE-2021-1234", want: nil},
+	}
+	for _, test := range tests {
+		if got := References(test.text); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("references of %q are %v, want %v", test.text, got, test.want)
+		}
+	}
+}
+
+func TestHasKeyword(t *testing.T) {
+	tests := []struct {
+		title string
+		want  bool
+	}{
+		{title: "Fix CVE-2021-1234 in the parser", want: true},
+		{title: "Address the cve reported by the scan", want: true},
+		{title: "Security: bump the TLS library", want: true},
+		{title: "Rename the cvelib package", want: false},
+		{title: "Update the securityContext of the chart", want: false},
+		{title: "Add a curve to the chart", want: false},
+	}
+	for _, test := range tests {
+		if got := hasKeyword(test.title, DefaultKeywords); got != test.want {
+			t.Errorf("%q matches the keywords: %v, want %v", test.title, got, test.want)
+		}
+	}
+}
+
+func TestDigest(t *testing.T) {
+	published := time.Date(2021, 5, 3, 0, 0, 0, 0, time.UTC)
+	advisories := []configs.SecurityList{{
+		Repository: "fabric",
+		Advisories: []configs.Advisory{
+			{GHSAID: "GHSA-aaaa-bbbb-cccc", Severity: "low"},
+			{GHSAID: "GHSA-jfh8-c2jp-5v3q", CVEID: "CVE-2021-44228", Severity: "critical",
+				Vulnerabilities: []configs.AdvisoryVulnerability{{AffectedVersions: "< 2.3.2", PatchedVersions: "2.3.2"}}},
+			{GHSAID: "GHSA-mmmm-pppp-qqqq", Severity: "unknown"},
+			{GHSAID: "GHSA-rrrr-vvvv-wwww", Severity: "High"},
+		},
+	}}
+	releases := []configs.ReleaseList{{
+		Repository: "fabric",
+		Releases: []github.RepositoryRelease{
+			{Name: github.String("v2.3.2"), TagName: github.String("v2.3.2"), Body: github.String("Fixes CVE-2021-44228"),
+				PublishedAt: &github.Timestamp{Time: published}},
+			{Name: github.String("v2.3.3"), TagName: github.String("v2.3.3"), Body: github.String("Performance"),
+				PublishedAt: &github.Timestamp{Time: published}},
+			{Name: github.String("Security update"), TagName: github.String("v2.3.4"),
+				PublishedAt: &github.Timestamp{Time: published}},
+		},
+	}}
+	prs := []configs.PrList{{
+		Repository: "besu",
+		PRs: []github.PullRequest{
+			{Number: github.Int(1), Title: github.String("Patch GHSA-jfh8-c2jp-5v3q")},
+			{Number: github.Int(2), Title: github.String("Bump the dependencies"),
+				Labels: []*github.Label{{Name: github.String("Security")}}},
+			{Number: github.Int(3), Title: github.String("Refactor the cvelib wrapper")},
+		},
+	}}
+	digest := Digest(advisories, releases, prs, configs.SecurityConfiguration{})
+	if len(digest) != 2 || digest[0].Repository != "fabric" || digest[1].Repository != "besu" {
+		t.Fatalf("got %+v, want fabric and besu", digest)
+	}
+
+	var severities []string
+	for _, advisory := range digest[0].Advisories {
+		severities = append(severities, advisory.Severity)
+	}
+	if !reflect.DeepEqual(severities, []string{"critical", "High", "low", "unknown"}) {
+		t.Errorf("got the severities %v, want the most severe first", severities)
+	}
+
+	fixes := digest[0].Releases
+	if len(fixes) != 2 {
+		t.Fatalf("got %v release fixes, want the one referring to a CVE and the one with a keyword", len(fixes))
+	}
+	// matched to the advisory by its CVE
+	if fixes[0].Severity != "critical" || fixes[0].AffectedVersions != "< 2.3.2" || fixes[0].PatchedVersions != "2.3.2" {
+		t.Errorf("got the release fix %+v, want the advisory severity and versions", fixes[0])
+	}
+	if fixes[1].Severity != "" || fixes[1].PatchedVersions != "v2.3.4" {
+		t.Errorf("got the release fix %+v, want its tag as the patched version", fixes[1])
+	}
+
+	var numbers []int
+	for _, fix := range digest[1].PRs {
+		numbers = append(numbers, fix.Number)
+	}
+	if !reflect.DeepEqual(numbers, []int{1, 2}) {
+		t.Errorf("got the PR fixes %v, want the GHSA reference and the label", numbers)
+	}
+	// matched to the advisory by its GHSA across the repositories
+	if digest[1].PRs[0].Severity != "critical" {
+		t.Errorf("got the PR fix %+v, want the advisory severity", digest[1].PRs[0])
+	}
+}
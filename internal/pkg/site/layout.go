@@ -0,0 +1,130 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package site
+
+// layout has the templates of all the pages, the content depends
+// on the kind of page being rendered
+const layout = `
+{{define "page" -}}
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="utf-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1">
+    <title>{{.Title}}{{if ne .Title .Site.Title}} - {{.Site.Title}}{{end}}</title>
+    <link rel="stylesheet" href="{{.Root}}style.css">
+</head>
+<body>
+    <nav>
+        <a href="{{.Root}}index.html">{{.Site.Title}}</a>
+        {{range .Orgs}}<a href="{{$.Root}}orgs/{{slug .Name}}/index.html">{{.Name}}</a>{{end}}
+    </nav>
+    <main>
+        <h1>{{.Title}}</h1>
+        {{if .Issue.Path}}{{template "issue" .}}
+        {{else if .Repo.Name}}{{template "repo" .}}
+        {{else if .Org.Name}}{{template "org" .}}
+        {{else}}{{template "index" .}}{{end}}
+    </main>
+</body>
+</html>
+{{end}}
+
+{{define "index"}}
+<ol class="issues">
+    {{range .Issues}}
+    <li><a href="{{$.Root}}{{.Path}}/index.html">{{.Title}}</a></li>
+    {{else}}
+    <li>No issues yet</li>
+    {{end}}
+</ol>
+{{end}}
+
+{{define "issue"}}
+{{range .Issue.Sections}}
+<section>
+    <h2>{{.Title}}</h2>
+    {{range .Organizations}}
+    {{$org := .Name}}
+    <h3><a href="{{$.Root}}orgs/{{slug .Name}}/index.html">{{.Name}}</a></h3>
+    {{range .Repositories}}
+    <h4><a href="{{$.Root}}orgs/{{slug $org}}/{{slug .Name}}/index.html">{{.Name}}</a></h4>
+    {{template "items" .Items}}
+    {{end}}
+    {{end}}
+</section>
+{{end}}
+{{end}}
+
+{{define "org"}}
+<ul class="repos">
+    {{range .Org.Repos}}
+    <li><a href="{{slug .Name}}/index.html">{{.Name}}</a> in {{len .Weeks}} issues</li>
+    {{end}}
+</ul>
+{{end}}
+
+{{define "repo"}}
+<p><a href="{{.Repo.URL}}">{{.Repo.URL}}</a></p>
+{{range .Repo.Weeks}}
+<section>
+    <h2><a href="{{$.Root}}{{.Issue.Path}}/index.html">{{.Issue.Title}}</a></h2>
+    {{range .Sections}}
+    <h3>{{.Title}}</h3>
+    {{template "items" .Items}}
+    {{end}}
+</section>
+{{end}}
+{{end}}
+
+{{define "items"}}
+<ul class="items">
+    {{range .}}
+    <li>
+        <a href="{{.URL}}">{{if .Number}}#{{.Number}} {{end}}{{.Title}}</a>
+        <span class="meta">by {{.Author.Login}}{{with .PublishedAt}} on {{date .}}{{else}}{{with .CreatedAt}} on {{date .}}{{end}}{{end}}</span>
+    </li>
+    {{end}}
+</ul>
+{{end}}
+`
+
+// stylesheet of the site, kept in line with the report css
+const stylesheet = `body {
+    font-family: 'Gill Sans', 'Gill Sans MT', Calibri, 'Trebuchet MS', sans-serif;
+    background-color: rgb(236, 231, 231);
+    margin: 0;
+}
+nav {
+    background-color: #21adad;
+    padding: 12px 30px;
+}
+nav a {
+    color: white;
+    margin-right: 18px;
+    text-decoration: none;
+}
+main {
+    background-color: whitesmoke;
+    margin: 30px;
+    padding: 20px;
+}
+.meta {
+    color: #666;
+    font-size: 0.9em;
+}
+`
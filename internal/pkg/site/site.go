@@ -0,0 +1,363 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package site builds the newsletter history as a static site. Each
+// run writes its data into a dated issue folder, then the index, the
+// organization and the repository pages and the sitemap are rebuilt
+// from all the issues found under the root.
+package site
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"github-updates/internal/pkg/configs"
+	"github-updates/internal/pkg/schema"
+	"html/template"
+	"io/ioutil"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// issueFolder matches the folders of the issues, such as 2021/week-07
+var issueFolder = regexp.MustCompile(`^(\d{4})/week-(\d{2})$`)
+
+// slugInvalid matches the characters replaced in the slugs
+var slugInvalid = regexp.MustCompile(`[^a-z0-9._-]+`)
+
+// kindTitles are the section headings of the report kinds
+var kindTitles = map[string]string{
+	configs.ReleaseReport:     "Releases",
+	configs.PullRequestReport: "Pull requests",
+	configs.IssueReport:       "Issues",
+}
+
+// kindOrder is the order of the sections in the pages
+var kindOrder = []string{configs.ReleaseReport, configs.PullRequestReport, configs.IssueReport}
+
+// Issue is a weekly issue of the newsletter
+type Issue struct {
+	Year      int
+	Week      int
+	Path      string
+	Documents []schema.Document
+}
+
+// Title names the issue by its week
+func (i Issue) Title() string {
+	return fmt.Sprintf("%v, week %v", i.Year, i.Week)
+}
+
+// Sections lists the documents in the order of the pages
+func (i Issue) Sections() []Section {
+	var sections []Section
+	for _, kind := range kindOrder {
+		for _, document := range i.Documents {
+			if document.Kind == kind {
+				sections = append(sections, Section{Title: kindTitles[kind], Organizations: document.Organizations})
+			}
+		}
+	}
+	return sections
+}
+
+// Section is a report kind in a page
+type Section struct {
+	Title         string
+	Organizations []schema.Organization
+}
+
+// Build writes the issue of the week and regenerates the pages
+func Build(siteConfig configs.SiteConfiguration, now time.Time, documents []schema.Document) error {
+	year, week := now.ISOWeek()
+	current := Issue{Year: year, Week: week, Path: issuePath(year, week), Documents: documents}
+	err := writeIssueData(siteConfig.Root, current)
+	if err != nil {
+		return err
+	}
+	issues, err := readIssues(siteConfig.Root)
+	if err != nil {
+		return err
+	}
+	return render(siteConfig, issues)
+}
+
+func issuePath(year int, week int) string {
+	return fmt.Sprintf("%d/week-%02d", year, week)
+}
+
+// writeIssueData keeps the documents of the run in the issue folder,
+// a rerun in the same week replaces them
+func writeIssueData(root string, issue Issue) error {
+	folder := filepath.Join(root, filepath.FromSlash(issue.Path))
+	err := os.MkdirAll(folder, 0755)
+	if err != nil {
+		return err
+	}
+	for _, document := range issue.Documents {
+		contents, err := json.MarshalIndent(document, "", "  ")
+		if err != nil {
+			return err
+		}
+		err = ioutil.WriteFile(filepath.Join(folder, document.Kind+".json"), contents, 0644)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readIssues loads every issue under the root, latest first
+func readIssues(root string) ([]Issue, error) {
+	var issues []Issue
+	years, err := ioutil.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+	for _, year := range years {
+		if !year.IsDir() {
+			continue
+		}
+		weeks, err := ioutil.ReadDir(filepath.Join(root, year.Name()))
+		if err != nil {
+			return nil, err
+		}
+		for _, week := range weeks {
+			match := issueFolder.FindStringSubmatch(year.Name() + "/" + week.Name())
+			if !week.IsDir() || match == nil {
+				continue
+			}
+			issue := Issue{Path: match[0]}
+			issue.Year, _ = strconv.Atoi(match[1])
+			issue.Week, _ = strconv.Atoi(match[2])
+			for _, kind := range kindOrder {
+				contents, err := ioutil.ReadFile(filepath.Join(root, year.Name(), week.Name(), kind+".json"))
+				if os.IsNotExist(err) {
+					continue
+				}
+				if err != nil {
+					return nil, err
+				}
+				var document schema.Document
+				err = json.Unmarshal(contents, &document)
+				if err != nil {
+					return nil, fmt.Errorf("reading %v of %v: %v", kind, issue.Path, err)
+				}
+				issue.Documents = append(issue.Documents, document)
+			}
+			issues = append(issues, issue)
+		}
+	}
+	sort.Slice(issues, func(first, second int) bool {
+		return issues[first].Path > issues[second].Path
+	})
+	return issues, nil
+}
+
+// page is the data of every rendered page
+type page struct {
+	Site  configs.SiteConfiguration
+	Title string
+	// Root is the relative path from the page to the site root
+	Root   string
+	Issue  Issue
+	Issues []Issue
+	Orgs   []orgPage
+	Org    orgPage
+	Repo   repoPage
+}
+
+type orgPage struct {
+	Name  string
+	Repos []repoPage
+}
+
+type repoPage struct {
+	Organization string
+	Name         string
+	URL          string
+	Weeks        []repoWeek
+}
+
+type repoWeek struct {
+	Issue    Issue
+	Sections []repoSection
+}
+
+type repoSection struct {
+	Title string
+	Items []schema.Item
+}
+
+// render writes all the pages and the sitemap
+func render(siteConfig configs.SiteConfiguration, issues []Issue) error {
+	t, err := template.New("site").Funcs(template.FuncMap{
+		"slug": slug,
+		"date": func(value *time.Time) string {
+			if value == nil {
+				return ""
+			}
+			return value.Format("2006-01-02")
+		},
+	}).Parse(layout)
+	if err != nil {
+		return err
+	}
+	orgs := organizations(issues)
+	var pages []string
+	write := func(pagePath string, data page) error {
+		data.Site = siteConfig
+		data.Issues = issues
+		data.Orgs = orgs
+		data.Root = strings.Repeat("../", strings.Count(pagePath, "/"))
+		pages = append(pages, pagePath)
+		fileName := filepath.Join(siteConfig.Root, filepath.FromSlash(pagePath))
+		err := os.MkdirAll(filepath.Dir(fileName), 0755)
+		if err != nil {
+			return err
+		}
+		f, err := os.Create(fileName)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return t.ExecuteTemplate(f, "page", data)
+	}
+
+	err = write("index.html", page{Title: siteConfig.Title})
+	if err != nil {
+		return err
+	}
+	for _, issue := range issues {
+		err = write(path.Join(issue.Path, "index.html"), page{Title: issue.Title(), Issue: issue})
+		if err != nil {
+			return err
+		}
+	}
+	for _, org := range orgs {
+		err = write(path.Join("orgs", slug(org.Name), "index.html"), page{Title: org.Name, Org: org})
+		if err != nil {
+			return err
+		}
+		for _, repo := range org.Repos {
+			err = write(
+				path.Join("orgs", slug(org.Name), slug(repo.Name), "index.html"),
+				page{Title: org.Name + "/" + repo.Name, Repo: repo},
+			)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	err = ioutil.WriteFile(filepath.Join(siteConfig.Root, "style.css"), []byte(stylesheet), 0644)
+	if err != nil {
+		return err
+	}
+	if siteConfig.BaseURL == "" {
+		// the sitemap protocol requires absolute locations
+		log.Println("The site has no base-url, the sitemap is not written")
+		return nil
+	}
+	return writeSitemap(siteConfig, pages)
+}
+
+// organizations regroups the issues by organization and repository
+func organizations(issues []Issue) []orgPage {
+	repos := map[string]map[string]*repoPage{}
+	for _, issue := range issues {
+		for _, kind := range kindOrder {
+			for _, document := range issue.Documents {
+				if document.Kind != kind {
+					continue
+				}
+				for _, org := range document.Organizations {
+					if repos[org.Name] == nil {
+						repos[org.Name] = map[string]*repoPage{}
+					}
+					for _, repo := range org.Repositories {
+						current := repos[org.Name][repo.Name]
+						if current == nil {
+							current = &repoPage{Organization: org.Name, Name: repo.Name, URL: repo.URL}
+							repos[org.Name][repo.Name] = current
+						}
+						last := len(current.Weeks) - 1
+						if last < 0 || current.Weeks[last].Issue.Path != issue.Path {
+							current.Weeks = append(current.Weeks, repoWeek{Issue: issue})
+							last++
+						}
+						current.Weeks[last].Sections = append(current.Weeks[last].Sections,
+							repoSection{Title: kindTitles[kind], Items: repo.Items})
+					}
+				}
+			}
+		}
+	}
+	var orgs []orgPage
+	for name, repoMap := range repos {
+		org := orgPage{Name: name}
+		for _, repo := range repoMap {
+			org.Repos = append(org.Repos, *repo)
+		}
+		sort.Slice(org.Repos, func(first, second int) bool {
+			return org.Repos[first].Name < org.Repos[second].Name
+		})
+		orgs = append(orgs, org)
+	}
+	sort.Slice(orgs, func(first, second int) bool {
+		return orgs[first].Name < orgs[second].Name
+	})
+	return orgs
+}
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Location string `xml:"loc"`
+}
+
+// writeSitemap lists the pages at their absolute location under the
+// base url of the site
+func writeSitemap(siteConfig configs.SiteConfiguration, pages []string) error {
+	urlSet := sitemapURLSet{Xmlns: "http://www.sitemaps.org/schemas/sitemap/0.9"}
+	base := strings.TrimRight(siteConfig.BaseURL, "/")
+	for _, pagePath := range pages {
+		location := strings.TrimSuffix(pagePath, "index.html")
+		urlSet.URLs = append(urlSet.URLs, sitemapURL{Location: base + "/" + location})
+	}
+	contents, err := xml.MarshalIndent(urlSet, "", "  ")
+	if err != nil {
+		return err
+	}
+	contents = append([]byte(xml.Header), contents...)
+	return ioutil.WriteFile(filepath.Join(siteConfig.Root, "sitemap.xml"), contents, 0644)
+}
+
+// slug makes a name safe for a path
+func slug(name string) string {
+	name = strings.ToLower(name)
+	return strings.Trim(slugInvalid.ReplaceAllString(name, "-"), "-")
+}
@@ -0,0 +1,172 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package stale finds the open PRs and issues which are stuck
+package stale
+
+import (
+	client2 "github-updates/internal/pkg/client"
+	"github-updates/internal/pkg/configs"
+	"log"
+	"time"
+)
+
+// DefaultThresholds apply when neither the organization nor the
+// report configure them
+var DefaultThresholds = configs.StaleThresholds{
+	InactiveDays:       30,
+	ReviewWaitDays:     7,
+	AssigneeSilentDays: 14,
+}
+
+// Thresholds of the organization, every value not set by the
+// organization falls back to the report and then to the default
+func Thresholds(
+	report configs.StaleThresholds,
+	organization configs.StaleThresholds,
+	issueTags []string,
+) configs.StaleThresholds {
+	pick := func(values ...int) int {
+		for _, value := range values {
+			if value > 0 {
+				return value
+			}
+		}
+		return 0
+	}
+	thresholds := configs.StaleThresholds{
+		InactiveDays:       pick(organization.InactiveDays, report.InactiveDays, DefaultThresholds.InactiveDays),
+		ReviewWaitDays:     pick(organization.ReviewWaitDays, report.ReviewWaitDays, DefaultThresholds.ReviewWaitDays),
+		AssigneeSilentDays: pick(organization.AssigneeSilentDays, report.AssigneeSilentDays, DefaultThresholds.AssigneeSilentDays),
+		IssueLabels:        organization.IssueLabels,
+	}
+	if len(thresholds.IssueLabels) == 0 {
+		thresholds.IssueLabels = report.IssueLabels
+	}
+	if len(thresholds.IssueLabels) == 0 {
+		thresholds.IssueLabels = issueTags
+	}
+	return thresholds
+}
+
+// Find the stuck PRs and issues of the repositories. A PR with no
+// activity is only reported as inactive, the PRs waiting on a
+// review are the ones with no reviewer requested and no review.
+func Find(
+	client client2.GHClientInterface,
+	org string,
+	repos []string,
+	thresholds configs.StaleThresholds,
+	now time.Time,
+) (configs.StaleDetails, error) {
+	details := configs.StaleDetails{
+		Organization: org,
+		Thresholds:   thresholds,
+	}
+	lists := map[string]*configs.StaleList{}
+	listOf := func(repo string) *configs.StaleList {
+		if lists[repo] == nil {
+			lists[repo] = &configs.StaleList{Repository: repo}
+		}
+		return lists[repo]
+	}
+
+	inactiveSince := now.AddDate(0, 0, -thresholds.InactiveDays)
+	reviewSince := now.AddDate(0, 0, -thresholds.ReviewWaitDays)
+	prLists, err := client.ListOpenPRs(org, repos)
+	if err != nil {
+		return configs.StaleDetails{}, err
+	}
+	for _, prList := range prLists {
+		for _, pr := range prList.PRs {
+			if pr.GetUpdatedAt().Before(inactiveSince) {
+				list := listOf(prList.Repository)
+				list.InactivePRs = append(list.InactivePRs, configs.StalePR{
+					PR:       pr,
+					IdleDays: days(now.Sub(pr.GetUpdatedAt())),
+				})
+				continue
+			}
+			if pr.GetDraft() || !pr.GetCreatedAt().Before(reviewSince) ||
+				len(pr.RequestedReviewers) != 0 || len(pr.RequestedTeams) != 0 {
+				continue
+			}
+			reviews, err := client.PullRequestReviews(org, prList.Repository, pr.GetNumber())
+			if err != nil {
+				return configs.StaleDetails{}, err
+			}
+			if len(reviews) != 0 {
+				continue
+			}
+			list := listOf(prList.Repository)
+			list.UnreviewedPRs = append(list.UnreviewedPRs, configs.StalePR{
+				PR:       pr,
+				IdleDays: days(now.Sub(pr.GetCreatedAt())),
+			})
+		}
+	}
+
+	silentSince := now.AddDate(0, 0, -thresholds.AssigneeSilentDays)
+	if len(thresholds.IssueLabels) != 0 {
+		issueLists, err := client.ListAssignedIssues(org, repos, thresholds.IssueLabels)
+		if err != nil {
+			return configs.StaleDetails{}, err
+		}
+		for _, issueList := range issueLists {
+			for _, issue := range issueList.Issues {
+				// the assignees can not have been silent for longer
+				if !issue.GetCreatedAt().Before(silentSince) {
+					continue
+				}
+				var assignees []string
+				for _, assignee := range issue.Assignees {
+					assignees = append(assignees, assignee.GetLogin())
+				}
+				lastActive, err :=
+					client.AssigneeLastActivity(org, issueList.Repository, issue.GetNumber(), assignees)
+				if err != nil {
+					return configs.StaleDetails{}, err
+				}
+				if lastActive.IsZero() {
+					lastActive = issue.GetCreatedAt()
+				}
+				if !lastActive.Before(silentSince) {
+					continue
+				}
+				list := listOf(issueList.Repository)
+				list.AbandonedIssues = append(list.AbandonedIssues, configs.StaleIssue{
+					Issue:              issue,
+					AssigneeLastActive: lastActive,
+					IdleDays:           days(now.Sub(lastActive)),
+				})
+			}
+		}
+	} else {
+		log.Printf("No issue labels for the abandoned issues of %v", org)
+	}
+
+	// keep the order of the repositories
+	for _, repo := range repos {
+		if list, found := lists[repo]; found {
+			details.StaleLists = append(details.StaleLists, *list)
+		}
+	}
+	return details, nil
+}
+
+func days(duration time.Duration) int {
+	return int(duration.Hours() / 24)
+}
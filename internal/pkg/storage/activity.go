@@ -0,0 +1,55 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"time"
+)
+
+// PullRequestsPerWeek counts the PRs opened in the organization
+// during each of the weeks before now, the oldest week first
+func (s *Store) PullRequestsPerWeek(org string, weeks int, now time.Time) ([]int, error) {
+	start := now.AddDate(0, 0, -7*weeks)
+	rows, err := s.db.Query(`
+		SELECT pull_requests.created_at
+		FROM pull_requests
+		JOIN repositories ON repositories.id = pull_requests.repository_id
+		WHERE repositories.organization = ? AND pull_requests.created_at >= ?`,
+		org, start.UTC().Format(time.RFC3339))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make([]int, weeks)
+	for rows.Next() {
+		var createdAt string
+		err = rows.Scan(&createdAt)
+		if err != nil {
+			return nil, err
+		}
+		created, err := time.Parse(time.RFC3339, createdAt)
+		if err != nil {
+			return nil, err
+		}
+		week := int(created.Sub(start).Hours() / (24 * 7))
+		if week >= 0 && week < weeks {
+			counts[week]++
+		}
+	}
+	return counts, rows.Err()
+}
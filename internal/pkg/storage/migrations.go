@@ -0,0 +1,136 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"log"
+	"time"
+)
+
+// migrations are applied in order, each one exactly once. Never
+// edit a released migration, append a new one instead.
+var migrations = []string{
+	// 1: initial schema
+	`
+	CREATE TABLE organizations (
+		login TEXT PRIMARY KEY,
+		name  TEXT NOT NULL
+	);
+	CREATE TABLE repositories (
+		id           INTEGER PRIMARY KEY AUTOINCREMENT,
+		github_id    INTEGER UNIQUE,
+		organization TEXT NOT NULL REFERENCES organizations (login),
+		name         TEXT NOT NULL,
+		url          TEXT NOT NULL
+	);
+	CREATE INDEX repositories_name ON repositories (organization, name);
+	CREATE TABLE pull_requests (
+		id            INTEGER PRIMARY KEY,
+		node_id       TEXT NOT NULL,
+		repository_id INTEGER NOT NULL REFERENCES repositories (id),
+		number        INTEGER NOT NULL,
+		title         TEXT NOT NULL,
+		author        TEXT NOT NULL,
+		state         TEXT NOT NULL,
+		labels        TEXT NOT NULL,
+		url           TEXT NOT NULL,
+		created_at    TEXT,
+		updated_at    TEXT,
+		merged_at     TEXT,
+		closed_at     TEXT,
+		first_seen_at TEXT NOT NULL,
+		last_seen_at  TEXT NOT NULL
+	);
+	CREATE INDEX pull_requests_created ON pull_requests (repository_id, created_at);
+	CREATE TABLE issues (
+		id            INTEGER PRIMARY KEY,
+		node_id       TEXT NOT NULL,
+		repository_id INTEGER NOT NULL REFERENCES repositories (id),
+		number        INTEGER NOT NULL,
+		title         TEXT NOT NULL,
+		author        TEXT NOT NULL,
+		state         TEXT NOT NULL,
+		labels        TEXT NOT NULL,
+		comments      INTEGER NOT NULL,
+		url           TEXT NOT NULL,
+		created_at    TEXT,
+		updated_at    TEXT,
+		closed_at     TEXT,
+		first_seen_at TEXT NOT NULL,
+		last_seen_at  TEXT NOT NULL
+	);
+	CREATE INDEX issues_created ON issues (repository_id, created_at);
+	CREATE TABLE releases (
+		id            INTEGER PRIMARY KEY,
+		node_id       TEXT NOT NULL,
+		repository_id INTEGER NOT NULL REFERENCES repositories (id),
+		tag_name      TEXT NOT NULL,
+		name          TEXT NOT NULL,
+		author        TEXT NOT NULL,
+		prerelease    BOOLEAN NOT NULL,
+		url           TEXT NOT NULL,
+		created_at    TEXT,
+		published_at  TEXT,
+		first_seen_at TEXT NOT NULL,
+		last_seen_at  TEXT NOT NULL
+	);
+	CREATE INDEX releases_published ON releases (repository_id, published_at);
+	`,
+}
+
+// migrate brings the database to the latest schema version
+func (s *Store) migrate() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    INTEGER PRIMARY KEY,
+			applied_at TEXT NOT NULL
+		)`)
+	if err != nil {
+		return err
+	}
+	var current int
+	err = s.db.QueryRow("SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&current)
+	if err != nil {
+		return err
+	}
+	for index := current; index < len(migrations); index++ {
+		version := index + 1
+		log.Printf("Migrating the history database to version %v", version)
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(migrations[index])
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		_, err = tx.Exec(
+			"INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)",
+			version, time.Now().UTC().Format(time.RFC3339),
+		)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		err = tx.Commit()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package storage keeps the history of the collected activity in a
+// SQLite database. Items are upserted by their GitHub ID, so that
+// the repeated runs build up the history instead of replacing it.
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"github-updates/internal/pkg/configs"
+	"github-updates/internal/pkg/schema"
+	"log"
+	"strings"
+	"time"
+
+	// pure Go SQLite driver, no cgo is needed
+	_ "modernc.org/sqlite"
+)
+
+// Store is the SQLite backed history of the activity
+type Store struct {
+	db *sql.DB
+}
+
+// Open creates or opens the database at the path and migrates
+// it to the latest schema
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+	// SQLite allows a single writer
+	db.SetMaxOpenConns(1)
+	_, err = db.Exec("PRAGMA foreign_keys = ON")
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	store := &Store{db: db}
+	err = store.migrate()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+// Close releases the database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// DB gives access to the database for the queries
+func (s *Store) DB() *sql.DB {
+	return s.db
+}
+
+// SaveOrganization upserts the organization by its GitHub login
+func (s *Store) SaveOrganization(org configs.OrganizationStructure) error {
+	_, err := s.db.Exec(`
+		INSERT INTO organizations (login, name) VALUES (?, ?)
+		ON CONFLICT (login) DO UPDATE SET name = excluded.name`,
+		org.Github, org.Name)
+	return err
+}
+
+// SaveDocument upserts the repositories and the items of the data
+// document in a single transaction
+func (s *Store) SaveDocument(document schema.Document) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	seenAt := document.GeneratedAt.UTC().Format(time.RFC3339)
+	for _, org := range document.Organizations {
+		_, err = tx.Exec(`
+			INSERT INTO organizations (login, name) VALUES (?, ?)
+			ON CONFLICT (login) DO NOTHING`,
+			org.Name, org.Name)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		for _, repo := range org.Repositories {
+			repositoryID, err := saveRepository(tx, org.Name, repo)
+			if err != nil {
+				tx.Rollback()
+				return err
+			}
+			for _, item := range repo.Items {
+				err = saveItem(tx, document.Kind, repositoryID, item, seenAt)
+				if err != nil {
+					tx.Rollback()
+					return fmt.Errorf("saving %v %v: %v", document.Kind, item.URL, err)
+				}
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+// saveRepository upserts the repository by its GitHub ID, a renamed
+// or transferred repository keeps its row. The repositories with no
+// ID, such as the ones of the older data files, are found by name.
+func saveRepository(tx *sql.Tx, org string, repo schema.Repository) (int64, error) {
+	var id int64
+	if repo.ID == 0 {
+		err := tx.QueryRow(
+			"SELECT id FROM repositories WHERE organization = ? AND name = ? ORDER BY id DESC LIMIT 1",
+			org, repo.Name,
+		).Scan(&id)
+		if err != sql.ErrNoRows {
+			return id, err
+		}
+		result, err := tx.Exec(
+			"INSERT INTO repositories (organization, name, url) VALUES (?, ?, ?)",
+			org, repo.Name, repo.URL)
+		if err != nil {
+			return 0, err
+		}
+		return result.LastInsertId()
+	}
+	// the rows saved without a GitHub ID get it by name
+	_, err := tx.Exec(`
+		UPDATE repositories SET github_id = ?
+		WHERE id = (
+			SELECT id FROM repositories
+			WHERE organization = ? AND name = ? AND github_id IS NULL
+			ORDER BY id DESC LIMIT 1
+		) AND NOT EXISTS (SELECT 1 FROM repositories WHERE github_id = ?)`,
+		repo.ID, org, repo.Name, repo.ID)
+	if err != nil {
+		return 0, err
+	}
+	_, err = tx.Exec(`
+		INSERT INTO repositories (github_id, organization, name, url) VALUES (?, ?, ?, ?)
+		ON CONFLICT (github_id) DO UPDATE SET
+			organization = excluded.organization, name = excluded.name, url = excluded.url`,
+		repo.ID, org, repo.Name, repo.URL)
+	if err != nil {
+		return 0, err
+	}
+	err = tx.QueryRow("SELECT id FROM repositories WHERE github_id = ?", repo.ID).Scan(&id)
+	return id, err
+}
+
+func saveItem(tx *sql.Tx, kind string, repositoryID int64, item schema.Item, seenAt string) error {
+	var err error
+	labels := strings.Join(item.Labels, ",")
+	switch kind {
+	case configs.PullRequestReport:
+		_, err = tx.Exec(`
+			INSERT INTO pull_requests (
+				id, node_id, repository_id, number, title, author, state, labels, url,
+				created_at, updated_at, merged_at, closed_at, first_seen_at, last_seen_at
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT (id) DO UPDATE SET
+				title = excluded.title, state = excluded.state, labels = excluded.labels,
+				updated_at = excluded.updated_at, merged_at = excluded.merged_at,
+				closed_at = excluded.closed_at, last_seen_at = excluded.last_seen_at`,
+			item.ID, item.NodeID, repositoryID, item.Number, item.Title, item.Author.Login,
+			item.State, labels, item.URL, timestamp(item.CreatedAt), timestamp(item.UpdatedAt),
+			timestamp(item.MergedAt), timestamp(item.ClosedAt), seenAt, seenAt)
+	case configs.IssueReport:
+		_, err = tx.Exec(`
+			INSERT INTO issues (
+				id, node_id, repository_id, number, title, author, state, labels, comments, url,
+				created_at, updated_at, closed_at, first_seen_at, last_seen_at
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT (id) DO UPDATE SET
+				title = excluded.title, state = excluded.state, labels = excluded.labels,
+				comments = excluded.comments, updated_at = excluded.updated_at,
+				closed_at = excluded.closed_at, last_seen_at = excluded.last_seen_at`,
+			item.ID, item.NodeID, repositoryID, item.Number, item.Title, item.Author.Login,
+			item.State, labels, item.Comments, item.URL, timestamp(item.CreatedAt),
+			timestamp(item.UpdatedAt), timestamp(item.ClosedAt), seenAt, seenAt)
+	case configs.ReleaseReport:
+		_, err = tx.Exec(`
+			INSERT INTO releases (
+				id, node_id, repository_id, tag_name, name, author, prerelease, url,
+				created_at, published_at, first_seen_at, last_seen_at
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT (id) DO UPDATE SET
+				tag_name = excluded.tag_name, name = excluded.name,
+				prerelease = excluded.prerelease, published_at = excluded.published_at,
+				last_seen_at = excluded.last_seen_at`,
+			item.ID, item.NodeID, repositoryID, item.TagName, item.Title, item.Author.Login,
+			item.Prerelease, item.URL, timestamp(item.CreatedAt), timestamp(item.PublishedAt),
+			seenAt, seenAt)
+	default:
+		log.Printf("No history table for %v, skipping", kind)
+	}
+	return err
+}
+
+// timestamp stores the time as RFC 3339 text, which SQLite date
+// functions understand
+func timestamp(value *time.Time) interface{} {
+	if value == nil {
+		return nil
+	}
+	return value.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,146 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github-updates/internal/pkg/configs"
+	"github-updates/internal/pkg/schema"
+)
+
+func openStore(t *testing.T) *Store {
+	store, err := Open(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func pullRequests(org string, repo schema.Repository, created ...time.Time) schema.Document {
+	for index := range created {
+		repo.Items = append(repo.Items, schema.Item{
+			ID:        repo.ID*100 + int64(len(repo.Items)) + 1,
+			Number:    len(repo.Items) + 1,
+			Title:     "change",
+			URL:       repo.URL,
+			CreatedAt: &created[index],
+		})
+	}
+	return schema.Document{
+		Kind:          configs.PullRequestReport,
+		GeneratedAt:   time.Now(),
+		Organizations: []schema.Organization{{Name: org, Repositories: []schema.Repository{repo}}},
+	}
+}
+
+func TestSaveRenamedRepository(t *testing.T) {
+	store := openStore(t)
+	now := time.Now().UTC()
+	err := store.SaveDocument(pullRequests("org", schema.Repository{ID: 7, Name: "old", URL: "https://github.com/org/old"}, now))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the second run sees the repository renamed, with one more PR
+	err = store.SaveDocument(pullRequests("org", schema.Repository{ID: 7, Name: "new", URL: "https://github.com/org/new"}, now, now))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	rows, err := store.DB().Query("SELECT name FROM repositories")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, name)
+	}
+	if !reflect.DeepEqual(names, []string{"new"}) {
+		t.Errorf("repositories are %v, want [new]", names)
+	}
+	var count int
+	err = store.DB().QueryRow(`
+		SELECT COUNT(*) FROM pull_requests
+		JOIN repositories ON repositories.id = pull_requests.repository_id
+		WHERE repositories.github_id = 7`).Scan(&count)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("the renamed repository has %v pull requests, want 2", count)
+	}
+}
+
+func TestSaveRepositoryWithoutID(t *testing.T) {
+	store := openStore(t)
+	now := time.Now().UTC()
+	// a data file written without the GitHub IDs, then a current one
+	err := store.SaveDocument(pullRequests("org", schema.Repository{Name: "repo", URL: "https://github.com/org/repo"}, now))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.SaveDocument(pullRequests("org", schema.Repository{ID: 9, Name: "repo", URL: "https://github.com/org/repo"}, now))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var count int
+	err = store.DB().QueryRow("SELECT COUNT(*) FROM repositories WHERE github_id = 9").Scan(&count)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var total int
+	err = store.DB().QueryRow("SELECT COUNT(*) FROM repositories").Scan(&total)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 || total != 1 {
+		t.Errorf("%v of %v repositories have the GitHub ID, want the single row", count, total)
+	}
+}
+
+func TestPullRequestsPerWeek(t *testing.T) {
+	store := openStore(t)
+	now := time.Date(2021, 5, 3, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	err := store.SaveDocument(pullRequests("org", schema.Repository{ID: 1, Name: "repo", URL: "https://github.com/org/repo"},
+		now.Add(-1*day), now.Add(-2*day), now.Add(-8*day), now.Add(-30*day)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.SaveDocument(pullRequests("other", schema.Repository{ID: 2, Name: "repo", URL: "https://github.com/other/repo"},
+		now.Add(-1*day)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	weekly, err := store.PullRequestsPerWeek("org", 2, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(weekly, []int{1, 2}) {
+		t.Errorf("weekly counts are %v, want [1 2]", weekly)
+	}
+}
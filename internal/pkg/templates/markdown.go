@@ -0,0 +1,201 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package templates
+
+import "github-updates/internal/pkg/configs"
+
+func init() {
+	register(configs.FormatMarkdown, configs.PullRequestReport, pullRequestMarkdown)
+	register(configs.FormatMarkdown, configs.ReleaseReport, releaseMarkdown)
+	register(configs.FormatMarkdown, configs.IssueReport, issueMarkdown)
+	register(configs.FormatMarkdown, configs.ContributorReport, contributorMarkdown)
+	register(configs.FormatMarkdown, configs.CycleTimeReport, cycleTimeMarkdown)
+	register(configs.FormatMarkdown, configs.StaleReport, staleMarkdown)
+	register(configs.FormatMarkdown, configs.ScorecardReport, scorecardMarkdown)
+	register(configs.FormatMarkdown, configs.ReleaseTimelineReport, releaseTimelineMarkdown)
+	register(configs.FormatMarkdown, configs.CommitReport, commitMarkdown)
+	register(configs.FormatMarkdown, configs.DiscussionReport, discussionMarkdown)
+	register(configs.FormatMarkdown, configs.SecurityReport, securityMarkdown)
+	register(configs.FormatMarkdown, configs.MilestoneReport, milestoneMarkdown)
+}
+
+const pullRequestMarkdown = `# Pull requests{{with period .}} for {{.}}{{end}}
+{{range .}}
+## {{escape .Organization}}
+{{with .Comparison}}
+{{.Current}} pull requests ({{.Previous}} on {{date .Since}}, {{trend .Percent}}){{range .Repositories}}{{if .New}}, {{escape .Repository}} was added{{end}}{{if .Removed}}, {{escape .Repository}} was removed{{end}}{{end}}
+{{end}}{{with .Charts}}
+{{with .PRsPerRepository}}![PRs per repository]({{.}}) {{end}}{{with .WeeklyActivity}}![PRs opened per week]({{.}}){{end}}
+{{end}}{{range .PrRepoLists}}
+### {{escape .Repository}}
+
+{{range .PRs -}}
+- [{{escape .GetTitle}}]({{.GetHTMLURL}}) by [@{{.GetUser.GetLogin}}]({{.GetUser.GetHTMLURL}})
+{{end}}{{end}}{{if .NewContributors}}
+### New contributors{{with period .Days}} in {{.}}{{end}}
+
+{{range .NewContributors -}}
+- [@{{.Login}}]({{.ProfileURL}}) opened [{{escape .Title}}]({{.URL}}) in {{escape .Repository}}
+{{end}}{{end}}{{end}}`
+
+const releaseMarkdown = `# Releases{{with period .}} for {{.}}{{end}}
+{{range .}}
+## {{escape .Organization}}
+{{with .Comparison}}
+{{.Current}} releases ({{.Previous}} on {{date .Since}}, {{trend .Percent}}){{range .Repositories}}{{if .New}}, {{escape .Repository}} was added{{end}}{{if .Removed}}, {{escape .Repository}} was removed{{end}}{{end}}
+{{end}}{{with .Charts}}{{with .ReleaseTimeline}}
+![Releases]({{.}})
+{{end}}{{end}}{{range .ReleaseRepoLists}}
+### {{escape .Repository}}
+{{range .Releases}}
+#### [{{escape .GetName}}]({{.GetHTMLURL}})
+
+Published by [@{{.GetAuthor.GetLogin}}]({{.GetAuthor.GetHTMLURL}}) on {{date .PublishedAt}}, tag ` + "`{{.GetTagName}}`" + `
+
+{{quote .GetBody}}
+{{end}}{{end}}{{end}}`
+
+const issueMarkdown = `# Issues worth your attention
+{{range .}}
+## {{escape .Organization}}
+{{with .Comparison}}
+{{.Current}} issues ({{.Previous}} on {{date .Since}}, {{trend .Percent}}){{range .Repositories}}{{if .New}}, {{escape .Repository}} was added{{end}}{{if .Removed}}, {{escape .Repository}} was removed{{end}}{{end}}
+{{end}}{{range .IssueLists}}
+### {{escape .Repository}}
+
+{{range .Issues -}}
+- [#{{.GetNumber}} {{escape .GetTitle}}]({{.GetHTMLURL}}){{range .Labels}} ` + "`{{.GetName}}`" + `{{end}}, {{.GetComments}} comments, opened on {{date .CreatedAt}}
+{{end}}{{end}}{{end}}`
+
+const contributorMarkdown = `# Most active contributors
+{{range .}}
+## {{escape .Organization}}
+
+| Contributor | PRs opened | PRs merged | Reviews given | Issues opened |
+| --- | ---: | ---: | ---: | ---: |
+{{range .Contributors -}}
+| [@{{.Login}}]({{.ProfileURL}}) | {{.PRsOpened}} | {{.PRsMerged}} | {{.ReviewsGiven}} | {{.IssuesOpened}} |
+{{end}}{{end}}`
+
+const cycleTimeMarkdown = `# Pull request cycle time
+{{range .}}
+## {{escape .Organization}}
+
+| Repository | PRs | Merged | First review median (h) | First review p90 (h) | Merge median (h) | Merge p90 (h) | Review rounds median |
+| --- | ---: | ---: | ---: | ---: | ---: | ---: | ---: |
+{{range .PrRepoLists}}{{$repository := .Repository}}{{with .CycleTimeSummary -}}
+| {{escape $repository}} | {{.PRs}} | {{.Merged}} | {{.TimeToFirstReview.Median}} | {{.TimeToFirstReview.P90}} | {{.TimeToMerge.Median}} | {{.TimeToMerge.P90}} | {{.ReviewRounds.Median}} |
+{{end}}{{end}}{{end}}`
+
+const staleMarkdown = `# What's stuck
+{{range .}}{{$thresholds := .Thresholds}}
+## {{escape .Organization}}
+{{range .StaleLists}}
+### {{escape .Repository}}
+{{if .InactivePRs}}
+PRs with no activity for {{$thresholds.InactiveDays}} days or more:
+
+{{range .InactivePRs -}}
+- [#{{.PR.GetNumber}} {{escape .PR.GetTitle}}]({{.PR.GetHTMLURL}}) by [@{{.PR.GetUser.GetLogin}}]({{.PR.GetUser.GetHTMLURL}}), idle for {{.IdleDays}} days
+{{end}}{{end}}{{if .UnreviewedPRs}}
+PRs waiting on a reviewer:
+
+{{range .UnreviewedPRs -}}
+- [#{{.PR.GetNumber}} {{escape .PR.GetTitle}}]({{.PR.GetHTMLURL}}) by [@{{.PR.GetUser.GetLogin}}]({{.PR.GetUser.GetHTMLURL}}), opened {{.IdleDays}} days ago
+{{end}}{{end}}{{if .AbandonedIssues}}
+Issues whose assignee has gone silent:
+
+{{range .AbandonedIssues -}}
+- [#{{.Issue.GetNumber}} {{escape .Issue.GetTitle}}]({{.Issue.GetHTMLURL}}) assigned to{{range .Issue.Assignees}} [@{{.GetLogin}}]({{.GetHTMLURL}}){{end}}, silent for {{.IdleDays}} days
+{{end}}{{end}}{{end}}{{end}}`
+
+const scorecardMarkdown = `# Repository health scorecard
+{{range .}}
+## {{escape .Organization}}
+
+| Repository | Score | Commits | Merged PRs | Mergers | Releases | Last release | Issues opened | Issues closed | Community files | Branch protection |
+| --- | ---: | ---: | ---: | ---: | ---: | --- | ---: | ---: | --- | --- |
+{{range .Repositories -}}
+| [{{escape .Repository}}]({{.URL}}) | {{.Score.Total}} | {{.Signals.Commits}} | {{.Signals.MergedPRs}} | {{.Signals.Mergers}} | {{.Signals.Releases}} | {{date .Signals.LastRelease}} | {{.Signals.IssuesOpened}} | {{.Signals.IssuesClosed}} | {{if .Signals.Readme}}README {{end}}{{if .Signals.License}}LICENSE {{end}}{{if .Signals.Contributing}}CONTRIBUTING {{end}}{{if .Signals.Security}}SECURITY{{end}} | {{if .Signals.BranchProtection}}yes{{else}}no{{end}} |
+{{end}}{{end}}`
+
+const releaseTimelineMarkdown = `# Release cadence
+{{range .}}{{$sla := .SLADays}}
+## {{escape .Organization}}
+
+| Repository | Releases | Last release | Days since | Average days between |{{if $sla}} Within {{$sla}} days |{{end}}
+| --- | ---: | --- | ---: | ---: |{{if $sla}} --- |{{end}}
+{{range .Repositories -}}
+| [{{escape .Repository}}]({{.URL}}) | {{.Releases}} | {{date .LastRelease}} | {{with .DaysSinceLast}}{{.}}{{end}} | {{with .AverageDaysBetween}}{{.}}{{end}} |{{if $sla}} {{if .OverSLA}}**no**{{else}}yes{{end}} |{{end}}
+{{end}}{{end}}`
+
+const commitMarkdown = `# Commits to the default branches
+{{range .}}
+## {{escape .Organization}}
+{{range .CommitLists}}
+### {{escape .Repository}}
+
+{{len .Commits}} commits to ` + "`{{.Branch}}`" + ` by {{.Authors}} authors{{if .FilesChanged}}, {{.FilesChanged}} files changed (+{{.Additions}} -{{.Deletions}}){{end}}
+
+{{range .Commits -}}
+- [{{escape .Subject}}]({{.URL}}) by {{with .Author}}{{if .Login}}[@{{.Login}}]({{.ProfileURL}}){{else}}{{escape .Name}}{{end}}{{end}}{{range .CoAuthors}}, {{if .Login}}[@{{.Login}}]({{.ProfileURL}}){{else}}{{escape .Name}}{{end}}{{end}}
+{{end}}{{end}}{{end}}`
+
+const discussionMarkdown = `# Discussions
+{{range .}}
+## {{escape .Organization}}
+{{range .DiscussionLists}}
+### {{escape .Repository}}
+{{range .Categories}}
+#### {{with .Emoji}}{{.}} {{end}}{{escape .Name}}
+{{if .New}}
+New discussions
+
+{{range .New -}}
+- [{{escape .Title}}]({{.URL}}){{if .Author}} by [@{{.Author}}]({{.AuthorURL}}){{end}} ({{.Comments}} comments)
+{{end}}{{end}}{{if .Answered}}
+Answered questions
+
+{{range .Answered -}}
+- [{{escape .Title}}]({{.URL}}), [answer]({{.AnswerURL}}){{if .AnsweredBy}} by [@{{.AnsweredBy}}]({{.AnsweredByURL}}){{end}}
+{{end}}{{end}}{{end}}{{end}}{{end}}`
+
+const securityMarkdown = `# Security advisories and fixes
+{{range .}}
+## {{escape .Organization}}
+{{range .SecurityLists}}
+### {{escape .Repository}}
+{{range .Advisories}}
+- **{{.Severity}}** [{{.GHSAID}}]({{.URL}}){{with .CVEID}} ({{.}}){{end}}: {{escape .Summary}}, published on {{date .PublishedAt}}
+{{range .Vulnerabilities}}  - {{with .Package}}{{escape .}}: {{end}}affected {{with .AffectedVersions}}` + "`{{.}}`" + `{{else}}unknown{{end}}, patched {{with .PatchedVersions}}` + "`{{.}}`" + `{{else}}not yet{{end}}
+{{end}}{{end}}{{range .Releases}}
+- Release [{{escape .Title}}]({{.URL}}){{with .Severity}} **{{.}}**{{end}}{{with .References}} fixes {{range $index, $id := .}}{{if $index}}, {{end}}{{$id}}{{end}}{{end}}{{with .AffectedVersions}}, affected ` + "`{{.}}`" + `{{end}}{{with .PatchedVersions}}, patched ` + "`{{.}}`" + `{{end}}
+{{end}}{{range .PRs}}
+- PR [{{escape .Title}}]({{.URL}}) by [@{{.Author}}]({{.AuthorURL}}){{with .Severity}} **{{.}}**{{end}}{{with .References}} fixes {{range $index, $id := .}}{{if $index}}, {{end}}{{$id}}{{end}}{{end}}{{with .AffectedVersions}}, affected ` + "`{{.}}`" + `{{end}}{{with .PatchedVersions}}, patched ` + "`{{.}}`" + `{{end}}
+{{end}}{{end}}{{end}}`
+
+const milestoneMarkdown = `# Upcoming releases
+{{range .}}
+## {{escape .Organization}}
+{{range .MilestoneLists}}
+### {{escape .Repository}}
+
+| Milestone | Due | Complete | Open | Closed | Closed this week |
+| --- | --- | ---: | ---: | ---: | ---: |
+{{range .Milestones -}}
+| [{{escape .Title}}]({{.URL}}) | {{with .DueOn}}{{date .}}{{else}}no due date{{end}}{{if .Overdue}} **overdue**{{end}} | {{.PercentComplete}}% | {{.OpenIssues}} | {{.ClosedIssues}} | {{.ClosedRecently}} |
+{{end}}{{end}}{{end}}`
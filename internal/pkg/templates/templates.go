@@ -0,0 +1,164 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package templates
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"text/template"
+)
+
+// builtIn has the default template for each report kind
+// keyed by the output format
+var builtIn = map[string]map[string]string{}
+
+// register adds a built-in template for the format and report kind
+func register(format string, kind string, text string) {
+	if builtIn[format] == nil {
+		builtIn[format] = map[string]string{}
+	}
+	builtIn[format][kind] = text
+}
+
+// Funcs are the helper functions available to every template
+var Funcs = template.FuncMap{
+	"escape": EscapeMarkdown,
+	"quote":  QuoteMarkdown,
+	"date":   formatDate,
+	"json":   toJSON,
+	"trend":  formatTrend,
+	"period": formatPeriod,
+}
+
+// Load returns the template to render the report kind in the given
+// format. The file is parsed when set, else the built-in template
+// for the format is used.
+func Load(format string, kind string, file string) (*template.Template, error) {
+	if file != "" {
+		return template.New(filepath.Base(file)).Funcs(Funcs).ParseFiles(file)
+	}
+	text, ok := builtIn[format][kind]
+	if !ok {
+		return nil, fmt.Errorf("no built-in %v template for %v, set the template file", format, kind)
+	}
+	return template.New(kind + "." + format).Funcs(Funcs).Parse(text)
+}
+
+// HasBuiltIn tells if there is a default template for the format
+func HasBuiltIn(format string) bool {
+	_, ok := builtIn[format]
+	return ok
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	"*", `\*`,
+	"_", `\_`,
+	"[", `\[`,
+	"]", `\]`,
+	"<", `\<`,
+	">", `\>`,
+	"|", `\|`,
+	"#", `\#`,
+)
+
+// EscapeMarkdown escapes the characters which markdown would
+// otherwise interpret, to be used for titles and names
+func EscapeMarkdown(text string) string {
+	return markdownEscaper.Replace(strings.TrimSpace(text))
+}
+
+// QuoteMarkdown turns a free form text such as the release body
+// into a markdown block quote
+func QuoteMarkdown(text string) string {
+	text = strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n"))
+	if text == "" {
+		return ""
+	}
+	return "> " + strings.ReplaceAll(text, "\n", "\n> ")
+}
+
+// formatDate prints the date part of a timestamp, it accepts
+// both time.Time and the github.Timestamp
+func formatDate(value interface{}) (string, error) {
+	if value == nil || (reflect.ValueOf(value).Kind() == reflect.Ptr && reflect.ValueOf(value).IsNil()) {
+		return "", nil
+	}
+	if t, ok := value.(interface{ Format(string) string }); ok {
+		return t.Format("2006-01-02"), nil
+	}
+	return "", errors.New("date expects a timestamp")
+}
+
+// toJSON encodes the value for the JSON bodies, a string is
+// returned quoted and escaped
+func toJSON(value interface{}) (string, error) {
+	contents, err := json.Marshal(value)
+	return string(contents), err
+}
+
+// formatTrend describes a change in percent such as "up 30%", it
+// accepts the Percent of the comparisons which is not set when there
+// was nothing to compare with
+func formatTrend(percent *float64) string {
+	switch {
+	case percent == nil:
+		return "new"
+	case *percent > 0:
+		return fmt.Sprintf("up %v%%", *percent)
+	case *percent < 0:
+		return fmt.Sprintf("down %v%%", -*percent)
+	}
+	return "unchanged"
+}
+
+// formatPeriod describes the window of a report such as "the last
+// 7 days". It accepts the day count, or the organizations of the
+// report whose first entry carries the days, and is empty when the
+// days are unknown.
+func formatPeriod(value interface{}) string {
+	days := 0
+	switch v := reflect.ValueOf(value); v.Kind() {
+	case reflect.Int:
+		days = int(v.Int())
+	case reflect.Slice:
+		if v.Len() == 0 || v.Index(0).Kind() != reflect.Struct {
+			break
+		}
+		if field := v.Index(0).FieldByName("Days"); field.Kind() == reflect.Int {
+			days = int(field.Int())
+		}
+	}
+	return Period(days)
+}
+
+// Period describes the last days such as "the last 7 days", it is
+// empty when the days are not set
+func Period(days int) string {
+	switch {
+	case days <= 0:
+		return ""
+	case days == 1:
+		return "the last day"
+	}
+	return fmt.Sprintf("the last %v days", days)
+}
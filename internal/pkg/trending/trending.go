@@ -0,0 +1,277 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package trending picks the top items for the summary files of the
+// external templates
+package trending
+
+import (
+	"fmt"
+	"github-updates/internal/pkg/configs"
+	"github-updates/internal/pkg/ranking"
+	"sort"
+	"time"
+
+	"github.com/google/go-github/v33/github"
+)
+
+// Sort keys of the trending items
+const (
+	SortCreated   = "created"
+	SortMerged    = "merged"
+	SortComments  = "comments"
+	SortReactions = "reactions"
+	SortStars     = "stars"
+	SortScore     = "score"
+)
+
+// DefaultCount of the items when it is not configured
+const DefaultCount = 5
+
+// candidate is an item with the values it can be sorted by
+type candidate struct {
+	repository string
+	created    time.Time
+	// merged is zero when the item is not merged
+	merged    time.Time
+	comments  int
+	reactions int
+	reviews   int
+	stars     int
+	author    string
+	// newContributor is nil when it is not known
+	newContributor *bool
+	score          ranking.Score
+}
+
+// PullRequest is a trending PR with its engagement score
+type PullRequest struct {
+	github.PullRequest
+	Score ranking.Score
+}
+
+// Issue is a trending issue with its engagement score
+type Issue struct {
+	github.Issue
+	Score ranking.Score
+}
+
+// Release is a trending release with its engagement score
+type Release struct {
+	github.RepositoryRelease
+	Score ranking.Score
+}
+
+// NeedsEngagement tells if the PR comments, reactions and reviews
+// have to be fetched for the sort key
+func NeedsEngagement(options configs.Trending) bool {
+	switch options.SortBy {
+	case SortComments, SortReactions, SortScore:
+		return true
+	}
+	return false
+}
+
+// TopPRs returns the top PRs across the repositories
+func TopPRs(details []configs.ExternalPRDetails, options configs.Trending) ([]PullRequest, error) {
+	var all []github.PullRequest
+	var candidates []candidate
+	for _, repo := range details {
+		for _, pr := range repo.PRs {
+			engagement := repo.Engagement[pr.GetNumber()]
+			all = append(all, pr)
+			prCandidate := candidate{
+				repository: repo.Organization.Github + "/" + repo.Repository.Name,
+				created:    pr.GetCreatedAt(),
+				merged:     pr.GetMergedAt(),
+				comments:   engagement.Comments,
+				reactions:  engagement.Reactions,
+				reviews:    engagement.Reviews,
+				stars:      repo.Repository.Stars,
+				author:     pr.GetUser().GetLogin(),
+			}
+			if repo.NewContributors != nil {
+				isNew := repo.NewContributors[prCandidate.author]
+				prCandidate.newContributor = &isNew
+			}
+			candidates = append(candidates, prCandidate)
+		}
+	}
+	selected, err := selectTop(candidates, options)
+	if err != nil {
+		return nil, err
+	}
+	top := make([]PullRequest, 0, len(selected))
+	for _, index := range selected {
+		top = append(top, PullRequest{all[index], candidates[index].score})
+	}
+	return top, nil
+}
+
+// TopIssues returns the top issues across the repositories
+func TopIssues(details []configs.ExternalIssueDetails, options configs.Trending) ([]Issue, error) {
+	if options.SortBy == SortMerged {
+		return nil, fmt.Errorf("issues can not be sorted by %v", SortMerged)
+	}
+	var all []github.Issue
+	var candidates []candidate
+	for _, repo := range details {
+		for _, issue := range repo.Issues {
+			all = append(all, issue)
+			candidates = append(candidates, candidate{
+				repository: repo.Organization.Github + "/" + repo.Repository.Name,
+				created:    issue.GetCreatedAt(),
+				comments:   issue.GetComments(),
+				reactions:  issue.GetReactions().GetTotalCount(),
+				stars:      repo.Repository.Stars,
+				author:     issue.GetUser().GetLogin(),
+			})
+		}
+	}
+	selected, err := selectTop(candidates, options)
+	if err != nil {
+		return nil, err
+	}
+	top := make([]Issue, 0, len(selected))
+	for _, index := range selected {
+		top = append(top, Issue{all[index], candidates[index].score})
+	}
+	return top, nil
+}
+
+// TopReleases returns the top releases across the repositories, the
+// release list carries no comments or reactions so their score only
+// counts the stars and the author novelty
+func TopReleases(details []configs.ExternalReleaseDetails, options configs.Trending) ([]Release, error) {
+	switch options.SortBy {
+	case SortMerged, SortComments, SortReactions:
+		return nil, fmt.Errorf("releases can not be sorted by %v", options.SortBy)
+	}
+	var all []github.RepositoryRelease
+	var candidates []candidate
+	for _, repo := range details {
+		for _, release := range repo.Releases {
+			all = append(all, release)
+			candidates = append(candidates, candidate{
+				repository: repo.Organization.Github + "/" + repo.Repository.Name,
+				created:    release.GetCreatedAt().Time,
+				stars:      repo.Repository.Stars,
+				author:     release.GetAuthor().GetLogin(),
+			})
+		}
+	}
+	selected, err := selectTop(candidates, options)
+	if err != nil {
+		return nil, err
+	}
+	top := make([]Release, 0, len(selected))
+	for _, index := range selected {
+		top = append(top, Release{all[index], candidates[index].score})
+	}
+	return top, nil
+}
+
+// selectTop scores and sorts the candidates and returns the indices
+// of the top ones, skipping a repository once it has reached its cap.
+// Fewer items are returned when there are not enough of them.
+func selectTop(candidates []candidate, options configs.Trending) ([]int, error) {
+	signals := make([]ranking.Signals, 0, len(candidates))
+	for _, c := range candidates {
+		signals = append(signals, ranking.Signals{
+			Reactions:      c.reactions,
+			Comments:       c.comments,
+			Reviews:        c.reviews,
+			Stars:          c.stars,
+			Author:         c.author,
+			NewContributor: c.newContributor,
+		})
+	}
+	for index, score := range ranking.Scores(signals, options.Weights) {
+		candidates[index].score = score
+	}
+
+	count := options.Count
+	if count <= 0 {
+		count = DefaultCount
+	}
+	less, err := comparator(options.SortBy)
+	if err != nil {
+		return nil, err
+	}
+	order := make([]int, len(candidates))
+	for index := range order {
+		order[index] = index
+	}
+	sort.SliceStable(order, func(first, second int) bool {
+		return less(candidates[order[first]], candidates[order[second]])
+	})
+
+	perRepository := map[string]int{}
+	var selected []int
+	for _, index := range order {
+		if len(selected) == count {
+			break
+		}
+		repository := candidates[index].repository
+		if options.PerRepoCap > 0 && perRepository[repository] >= options.PerRepoCap {
+			continue
+		}
+		perRepository[repository]++
+		selected = append(selected, index)
+	}
+	return selected, nil
+}
+
+// comparator orders the candidates for the sort key, higher values
+// first and the newest first on a tie
+func comparator(sortBy string) (func(first, second candidate) bool, error) {
+	newest := func(first, second candidate) bool {
+		return first.created.After(second.created)
+	}
+	byCount := func(value func(candidate) int) func(first, second candidate) bool {
+		return func(first, second candidate) bool {
+			if value(first) != value(second) {
+				return value(first) > value(second)
+			}
+			return newest(first, second)
+		}
+	}
+	switch sortBy {
+	case "", SortCreated:
+		return newest, nil
+	case SortMerged:
+		return func(first, second candidate) bool {
+			if !first.merged.Equal(second.merged) {
+				return first.merged.After(second.merged)
+			}
+			return newest(first, second)
+		}, nil
+	case SortComments:
+		return byCount(func(c candidate) int { return c.comments }), nil
+	case SortReactions:
+		return byCount(func(c candidate) int { return c.reactions }), nil
+	case SortStars:
+		return byCount(func(c candidate) int { return c.stars }), nil
+	case SortScore:
+		return func(first, second candidate) bool {
+			if first.score.Total != second.score.Total {
+				return first.score.Total > second.score.Total
+			}
+			return newest(first, second)
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown trending sort key %v", sortBy)
+}
@@ -0,0 +1,96 @@
+/**
+ * Copyright 2021 Hyperledger Community
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package trending
+
+import (
+	"testing"
+	"time"
+
+	"github-updates/internal/pkg/configs"
+
+	"github.com/google/go-github/v33/github"
+)
+
+func releases(repo string, tags ...string) configs.ExternalReleaseDetails {
+	details := configs.ExternalReleaseDetails{
+		Organization: configs.OrganizationStructure{Github: "org"},
+		Repository:   configs.RepositoryStructure{Name: repo},
+	}
+	created := time.Date(2021, 5, 3, 0, 0, 0, 0, time.UTC)
+	for index, tag := range tags {
+		details.Releases = append(details.Releases, github.RepositoryRelease{
+			TagName:   github.String(tag),
+			CreatedAt: &github.Timestamp{Time: created.AddDate(0, 0, index)},
+		})
+	}
+	return details
+}
+
+func TestTopFewerThanCount(t *testing.T) {
+	tests := []struct {
+		name    string
+		details []configs.ExternalReleaseDetails
+		options configs.Trending
+		want    []string
+	}{
+		{
+			name:    "no candidates",
+			details: nil,
+			options: configs.Trending{Count: 3},
+		},
+		{
+			name:    "fewer than the count",
+			details: []configs.ExternalReleaseDetails{releases("fabric", "v1", "v2")},
+			options: configs.Trending{Count: 5},
+			want:    []string{"v2", "v1"},
+		},
+		{
+			name:    "fewer than the default count",
+			details: []configs.ExternalReleaseDetails{releases("fabric", "v1")},
+			want:    []string{"v1"},
+		},
+		{
+			name: "fewer under the cap",
+			details: []configs.ExternalReleaseDetails{
+				releases("fabric", "v1", "v2", "v3"),
+				releases("besu", "21.1"),
+			},
+			options: configs.Trending{Count: 4, PerRepoCap: 1},
+			want:    []string{"v3", "21.1"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			top, err := TopReleases(test.details, test.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var tags []string
+			for _, release := range top {
+				tags = append(tags, release.GetTagName())
+			}
+			if len(tags) != len(test.want) {
+				t.Fatalf("got %v, want %v", tags, test.want)
+			}
+			for index := range tags {
+				if tags[index] != test.want[index] {
+					t.Errorf("got %v, want %v", tags, test.want)
+				}
+			}
+		})
+	}
+}
@@ -24,7 +24,7 @@ import (
 )
 
 func SaveIntoFile(v interface{}, fileName string) error {
-	fileContents, err := json.MarshalIndent(v, "", "")
+	fileContents, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
 		return err
 	}
@@ -37,13 +37,18 @@ func PrettyPrint(v interface{}, fileName string, templateFile string) error {
 	if err != nil {
 		return err
 	}
+	return PrettyPrintTemplate(v, fileName, t)
+}
+
+// PrettyPrintTemplate renders v with an already parsed template
+func PrettyPrintTemplate(v interface{}, fileName string, t *template.Template) error {
 
 	f, err := os.Create(fileName)
 	if err != nil {
 		return err
 	}
-	err = t.Execute(f, v)
-	return err
+	defer f.Close()
+	return t.Execute(f, v)
 }
 
 func GetEnvOrDefault(env, defaultValue string) string {